@@ -2,16 +2,21 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"os/exec"
 	"runtime"
+	"strconv"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gustavo-silva98/adnotes/internal/cli"
 	"github.com/gustavo-silva98/adnotes/internal/clientui/model"
 	"github.com/gustavo-silva98/adnotes/internal/clientui/update"
 	"github.com/gustavo-silva98/adnotes/internal/clientui/view"
+	"github.com/gustavo-silva98/adnotes/internal/repository/file"
 )
 
 type app struct {
@@ -31,12 +36,27 @@ func (a *app) View() string {
 }
 
 func main() {
+	// Subcomandos de linha de comando rodam no terminal atual, sem TUI.
+	if len(os.Args) > 1 && cli.IsCommand(os.Args[1]) {
+		os.Exit(cli.Run(context.Background(), os.Args[1:], cli.Env{
+			DBPath: cli.DefaultDBPath(),
+			Stdin:  os.Stdin,
+			Stdout: os.Stdout,
+			Stderr: os.Stderr,
+		}))
+	}
 
 	// A lógica para abrir o terminal é diferente por sistema operacional.
 	// O "client" vai executar um novo terminal e passar a si mesmo como argumento.
 	// Este é o método "double-exec".
 	if len(os.Args) > 1 && os.Args[len(os.Args)-1] == "in-terminal" {
-		m := model.New()
+		m, err := model.New()
+		if err != nil {
+			// O terminal aberto pelo servidor fecha ao sair; o log guarda o motivo.
+			file.WriteLog(err.Error(), "")
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
 
 		switch os.Args[1] {
 		case "InsertNote":
@@ -49,6 +69,16 @@ func main() {
 			m.State = model.InitServerState
 		case "AdvancedSearch":
 			m.State = model.FullSearchNoteState
+		case "Reminder":
+			// Formato: client Reminder <id> in-terminal
+			if len(os.Args) > 3 {
+				if id, err := strconv.Atoi(os.Args[2]); err == nil {
+					if note, err := m.DB.GetNote(m.Context, id); err == nil {
+						m.ReminderNote = note
+						m.State = model.ReminderState
+					}
+				}
+			}
 		}
 		p := tea.NewProgram(&app{Model: m})
 		if _, err := p.Run(); err != nil {
@@ -67,12 +97,11 @@ func main() {
 	var cmd *exec.Cmd
 	switch runtime.GOOS {
 	case "linux":
-		cmd = exec.Command("gnome-terminal", "--", "bash", "-c", fmt.Sprintf("%s in-terminal", exePath))
+		cmd = exec.Command("gnome-terminal", "--", "bash", "-c", fmt.Sprintf("%s %s in-terminal", exePath, strings.Join(os.Args[1:], " ")))
 	case "windows":
 		log.Println("o exePath é ", exePath)
-		cmd = exec.Command(
-			"cmd.exe", "/C", "start", "cmd.exe", "/C", exePath, os.Args[1], "in-terminal",
-		)
+		args := append([]string{"/C", "start", "cmd.exe", "/C", exePath}, os.Args[1:]...)
+		cmd = exec.Command("cmd.exe", append(args, "in-terminal")...)
 	default:
 		fmt.Println("Sistema operacional não suportado.")
 		return
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gustavo-silva98/adnotes/internal/config"
+	"golang.design/x/hotkey"
+)
+
+// registerRetryDelay evita que uma hotkey já ocupada por outro programa
+// prenda a goroutine em um loop de tentativas.
+const registerRetryDelay = 2 * time.Second
+
+var hotkeyKeys = map[string]hotkey.Key{
+	"a": hotkey.KeyA, "b": hotkey.KeyB, "c": hotkey.KeyC, "d": hotkey.KeyD, "e": hotkey.KeyE,
+	"f": hotkey.KeyF, "g": hotkey.KeyG, "h": hotkey.KeyH, "i": hotkey.KeyI, "j": hotkey.KeyJ,
+	"k": hotkey.KeyK, "l": hotkey.KeyL, "m": hotkey.KeyM, "n": hotkey.KeyN, "o": hotkey.KeyO,
+	"p": hotkey.KeyP, "q": hotkey.KeyQ, "r": hotkey.KeyR, "s": hotkey.KeyS, "t": hotkey.KeyT,
+	"u": hotkey.KeyU, "v": hotkey.KeyV, "w": hotkey.KeyW, "x": hotkey.KeyX, "y": hotkey.KeyY,
+	"z": hotkey.KeyZ,
+	"0": hotkey.Key0, "1": hotkey.Key1, "2": hotkey.Key2, "3": hotkey.Key3, "4": hotkey.Key4,
+	"5": hotkey.Key5, "6": hotkey.Key6, "7": hotkey.Key7, "8": hotkey.Key8, "9": hotkey.Key9,
+	"f1": hotkey.KeyF1, "f2": hotkey.KeyF2, "f3": hotkey.KeyF3, "f4": hotkey.KeyF4, "f5": hotkey.KeyF5,
+	"f6": hotkey.KeyF6, "f7": hotkey.KeyF7, "f8": hotkey.KeyF8, "f9": hotkey.KeyF9, "f10": hotkey.KeyF10,
+	"f11": hotkey.KeyF11, "f12": hotkey.KeyF12, "f13": hotkey.KeyF13, "f14": hotkey.KeyF14, "f15": hotkey.KeyF15,
+	"f16": hotkey.KeyF16, "f17": hotkey.KeyF17, "f18": hotkey.KeyF18, "f19": hotkey.KeyF19, "f20": hotkey.KeyF20,
+	"space": hotkey.KeySpace, "return": hotkey.KeyReturn, "escape": hotkey.KeyEscape,
+	"delete": hotkey.KeyDelete, "tab": hotkey.KeyTab,
+	"left": hotkey.KeyLeft, "right": hotkey.KeyRight, "up": hotkey.KeyUp, "down": hotkey.KeyDown,
+}
+
+// toHotkey converte uma combinação da configuração nos tipos da biblioteca de hotkeys.
+func toHotkey(c config.Combo) ([]hotkey.Modifier, hotkey.Key, error) {
+	key, ok := hotkeyKeys[c.Key]
+	if !ok {
+		return nil, 0, fmt.Errorf("tecla %q não suportada", c.Key)
+	}
+	mods := make([]hotkey.Modifier, 0, len(c.Modifiers))
+	for _, name := range c.Modifiers {
+		mod, ok := hotkeyModifiers[name]
+		if !ok {
+			return nil, 0, fmt.Errorf("modificador %q não suportado neste sistema", name)
+		}
+		mods = append(mods, mod)
+	}
+	return mods, key, nil
+}
+
+// hotkeyManager registra as hotkeys da configuração e permite trocá-las em
+// tempo de execução (reload-config).
+type hotkeyManager struct {
+	mu   sync.Mutex
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// Start registra uma goroutine por binding. Bindings que não puderem ser
+// convertidos são ignorados com log.
+func (h *hotkeyManager) Start(bindings []config.Binding) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.stop = make(chan struct{})
+	for _, b := range bindings {
+		mods, key, err := toHotkey(b.Combo)
+		if err != nil {
+			log.Printf("Hotkey %v de %q ignorada: %v", b.Combo, b.Action.Name, err)
+			continue
+		}
+		h.wg.Add(1)
+		go h.listen(b, mods, key, h.stop)
+	}
+}
+
+// Stop remove o registro de todas as hotkeys e espera as goroutines terminarem.
+func (h *hotkeyManager) Stop() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.stop != nil {
+		close(h.stop)
+		h.stop = nil
+	}
+	h.wg.Wait()
+}
+
+func (h *hotkeyManager) listen(b config.Binding, mods []hotkey.Modifier, key hotkey.Key, stop <-chan struct{}) {
+	defer h.wg.Done()
+	for {
+		// Registra a hotkey
+		hk := hotkey.New(mods, key)
+		if err := hk.Register(); err != nil {
+			log.Printf("Erro ao registrar hotkey %v (%v): %v", b.Combo, b.Action.Name, err)
+			select {
+			case <-stop:
+				return
+			case <-time.After(registerRetryDelay):
+				continue
+			}
+		}
+
+		// Usa select para poder cancelar
+		select {
+		case <-stop:
+			hk.Unregister()
+			return
+		case <-hk.Keydown():
+			executeTerminal(b.Action.State)
+			<-hk.Keyup() // Espera soltar a tecla
+		}
+		hk.Unregister()
+	}
+}
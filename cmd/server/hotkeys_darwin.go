@@ -0,0 +1,10 @@
+package main
+
+import "golang.design/x/hotkey"
+
+var hotkeyModifiers = map[string]hotkey.Modifier{
+	"ctrl":  hotkey.ModCtrl,
+	"shift": hotkey.ModShift,
+	"alt":   hotkey.ModOption,
+	"super": hotkey.ModCmd,
+}
@@ -0,0 +1,11 @@
+package main
+
+import "golang.design/x/hotkey"
+
+// No X11, Alt costuma ser Mod1 e a tecla Super, Mod4.
+var hotkeyModifiers = map[string]hotkey.Modifier{
+	"ctrl":  hotkey.ModCtrl,
+	"shift": hotkey.ModShift,
+	"alt":   hotkey.Mod1,
+	"super": hotkey.Mod4,
+}
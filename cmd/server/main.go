@@ -1,17 +1,28 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net"
+	"net/http"
 	"os"
 	"os/exec"
 	"os/signal"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"sync"
 	"syscall"
+	"time"
 
-	"golang.design/x/hotkey"
+	"github.com/gustavo-silva98/adnotes/internal/api"
+	"github.com/gustavo-silva98/adnotes/internal/config"
+	"github.com/gustavo-silva98/adnotes/internal/instance"
+	"github.com/gustavo-silva98/adnotes/internal/ipc"
+	"github.com/gustavo-silva98/adnotes/internal/repository/file"
+	"github.com/gustavo-silva98/adnotes/internal/scheduler"
 	"golang.design/x/hotkey/mainthread"
 )
 
@@ -24,6 +35,29 @@ var runState = struct {
 func main() { mainthread.Init(fn) }
 func fn() {
 	fmt.Println("Server iniciando...")
+	os.MkdirAll(file.DataDir, os.ModePerm)
+
+	// Garante uma única instância: um segundo server só pede à instância
+	// ativa que mostre a janela inicial e encerra.
+	lock, err := instance.Acquire(instance.LockPath())
+	if errors.Is(err, instance.ErrAlreadyRunning) {
+		pid, _ := instance.ReadPID(instance.LockPath())
+		fmt.Printf("PulseNote server já está em execução (PID %d).\n", pid)
+		if _, err := ipc.Call(ipc.SocketPath(), ipc.Request{Command: ipc.CmdOpenState, Args: []string{"InitServer"}}); err != nil {
+			log.Println("Erro ao acionar a instância em execução:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if err != nil {
+		log.Fatalln("Erro ao obter lock de instância única:", err)
+	}
+	defer func() {
+		if err := lock.Release(); err != nil {
+			log.Println("Erro ao liberar lock de instância única:", err)
+		}
+	}()
+
 	executeTerminal("InitServer")
 	// Captura sinais do sistema (como Ctrl+C)
 	sigs := make(chan os.Signal, 1)
@@ -32,145 +66,217 @@ func fn() {
 	// Canal para coordenar o encerramento de todas as goroutines
 	done := make(chan struct{})
 
-	wg := sync.WaitGroup{}
-	wg.Add(2)
+	var shutdownOnce sync.Once
+	shutdown := func() {
+		shutdownOnce.Do(func() {
+			log.Println("Encerrando...")
+			close(done) // Fecha o canal, sinalizando para todas as goroutines encerrarem
+		})
+	}
 
 	// Goroutine para capturar sinais e fechar o canal done
 	go func() {
 		<-sigs
-		log.Println("Encerrando...")
-		close(done) // Fecha o canal, sinalizando para todas as goroutines encerrarem
+		shutdown()
 	}()
 
-	go func() {
-		defer wg.Done()
-		for {
-			// Registra a hotkey
-			hk := hotkey.New([]hotkey.Modifier{hotkey.ModCtrl, hotkey.ModShift}, hotkey.KeyH)
-			err := hk.Register()
-			if err != nil {
-				log.Println("Erro ao registrar hotkey:", err)
-				continue
-			}
-
-			// Usa select para poder cancelar
-			select {
-			case <-done:
-				hk.Unregister()
-				return
-			case <-hk.Keydown():
-				executeTerminal("InsertNote")
-				<-hk.Keyup()
-			}
-			hk.Unregister()
+	hotkeys := &hotkeyManager{}
+	hotkeys.Start(loadHotkeyBindings())
+
+	// Canal de controle local usado pelo client (ping, shutdown, open-state, reload-config).
+	control, err := startControlServer(shutdown, hotkeys)
+	if err != nil {
+		log.Println("Erro ao iniciar canal de controle:", err)
+	} else {
+		defer control.Close()
+	}
+
+	// Agendador de lembretes: abre o cliente no estado "Reminder" para cada nota vencida.
+	ctx := context.Background()
+	db, err := file.InitDB(file.DataPath("banco.db"), ctx)
+	if err != nil {
+		log.Println("Erro ao abrir banco para lembretes:", err)
+	} else {
+		sched := scheduler.New(db, func(note file.Note) {
+			executeTerminal("Reminder", strconv.Itoa(note.ID))
+		})
+		go sched.Run(ctx, done)
+
+		// Lixeira: apaga as notas que passaram da retenção configurada.
+		if retention := loadTrashRetention(); retention > 0 {
+			go purgeTrashLoop(ctx, db, retention, done)
 		}
-	}()
 
-	go func() {
-		defer wg.Done()
-		for {
-
-			// Registra a hotkey
-			hk := hotkey.New([]hotkey.Modifier{hotkey.ModCtrl, hotkey.ModShift}, hotkey.KeyR)
-			err := hk.Register()
-			if err != nil {
-				log.Println("Erro ao registrar hotkey R:", err)
-				continue
-			}
-
-			// Usa select para poder cancelar
-			select {
-			case <-done:
-				hk.Unregister()
-				return
-			case <-hk.Keydown():
-				fmt.Println("Foi pressionado o R")
-				executeTerminal("ReadNote")
-				<-hk.Keyup() // Espera soltar a tecla
-			}
-			hk.Unregister()
+		// API HTTP local, desligada a menos que "api.enabled" esteja no config.json.
+		if srv, err := startAPIServer(db); err != nil {
+			log.Println("Erro ao iniciar API local:", err)
+		} else if srv != nil {
+			defer srv.Close()
 		}
-	}()
+	}
+
+	<-done
+	hotkeys.Stop()
+}
+
+// clientStates são os estados que podem ser abertos no client via open-state.
+var clientStates = map[string]bool{
+	"InsertNote":     true,
+	"ReadNote":       true,
+	"AdvancedSearch": true,
+	"ExecuteServer":  true,
+	"InitServer":     true,
+	"Reminder":       true,
+}
+
+// startControlServer abre o socket de controle e registra os comandos do server.
+func startControlServer(shutdown func(), hotkeys *hotkeyManager) (*ipc.Server, error) {
+	control, err := ipc.Listen(ipc.SocketPath())
+	if err != nil {
+		return nil, err
+	}
+
+	control.Handle(ipc.CmdPing, func(req ipc.Request) ipc.Response {
+		return ipc.Response{OK: true, Message: "pong"}
+	})
+	control.Handle(ipc.CmdShutdown, func(req ipc.Request) ipc.Response {
+		shutdown()
+		return ipc.Response{OK: true, Message: "server encerrando"}
+	})
+	control.Handle(ipc.CmdOpenState, func(req ipc.Request) ipc.Response {
+		if len(req.Args) == 0 || !clientStates[req.Args[0]] {
+			return ipc.Response{Error: fmt.Sprintf("estado inválido: %v", req.Args)}
+		}
+		if err := executeTerminal(req.Args...); err != nil {
+			return ipc.Response{Error: err.Error()}
+		}
+		return ipc.Response{OK: true}
+	})
+	control.Handle(ipc.CmdReloadConfig, func(req ipc.Request) ipc.Response {
+		// Com o config inválido, as hotkeys em uso continuam registradas.
+		cfg, err := config.Load(config.Path())
+		if err != nil {
+			log.Println("Erro ao recarregar a configuração, mantendo as hotkeys atuais:", err)
+			return ipc.Response{Error: err.Error()}
+		}
+		bindings, err := cfg.HotkeyBindings()
+		if err != nil {
+			log.Println("Erro nas hotkeys configuradas, mantendo as atuais:", err)
+			return ipc.Response{Error: err.Error()}
+		}
+		hotkeys.Stop()
+		hotkeys.Start(bindings)
+		return ipc.Response{OK: true, Message: fmt.Sprintf("%d hotkeys registradas", len(bindings))}
+	})
 
 	go func() {
-		defer wg.Done()
-		for {
-
-			// Registra a hotkey
-			hk := hotkey.New([]hotkey.Modifier{hotkey.ModCtrl, hotkey.ModShift}, hotkey.KeyD)
-			err := hk.Register()
-			if err != nil {
-				log.Println("Erro ao registrar hotkey A:", err)
-				continue
-			}
-
-			// Usa select para poder cancelar
-			select {
-			case <-done:
-				hk.Unregister()
-				return
-			case <-hk.Keydown():
-				executeTerminal("AdvancedSearch")
-				<-hk.Keyup() // Espera soltar a tecla
-			}
-			hk.Unregister()
+		if err := control.Serve(); err != nil {
+			log.Println("Erro no canal de controle:", err)
 		}
 	}()
+	return control, nil
+}
 
+// startAPIServer sobe a API HTTP no endereço de loopback configurado. Retorna
+// nil sem erro quando a API está desligada.
+func startAPIServer(db file.Writer) (*http.Server, error) {
+	cfg, err := config.Load(config.Path())
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.API.Enabled {
+		return nil, nil
+	}
+	ln, err := net.Listen("tcp", cfg.API.Addr)
+	if err != nil {
+		return nil, err
+	}
+	srv := &http.Server{Handler: api.New(db, cfg.API.Token), ReadHeaderTimeout: 5 * time.Second}
 	go func() {
-		defer wg.Done()
-		for {
-			// Registra a hotkey
-			hk := hotkey.New([]hotkey.Modifier{hotkey.ModCtrl, hotkey.ModShift}, hotkey.KeyK)
-			err := hk.Register()
-			if err != nil {
-				log.Println("Erro ao registrar hotkey K:", err)
-				continue
-			}
-
-			select {
-			case <-done:
-				hk.Unregister()
-				return
-			case <-hk.Keydown():
-				executeTerminal("ExecuteServer")
-				<-hk.Keyup()
-			}
-			hk.Unregister()
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Println("Erro na API local:", err)
 		}
 	}()
+	log.Printf("API local escutando em http://%v", ln.Addr())
+	return srv, nil
+}
+
+// loadTrashRetention lê a retenção da lixeira do config.json, usando o padrão
+// se o arquivo for inválido.
+func loadTrashRetention() time.Duration {
+	cfg, err := config.Load(config.Path())
+	if err != nil {
+		log.Println("Erro na configuração, usando retenção padrão da lixeira:", err)
+	}
+	return cfg.TrashRetention()
+}
+
+// purgeTrashLoop apaga as notas vencidas da lixeira na partida e depois uma
+// vez por dia, até done ser fechado.
+func purgeTrashLoop(ctx context.Context, db file.Writer, retention time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		n, err := db.PurgeTrash(ctx, time.Now().Add(-retention).Unix())
+		if err != nil {
+			log.Println("Erro ao limpar a lixeira:", err)
+		} else if n > 0 {
+			log.Printf("%d notas apagadas da lixeira", n)
+		}
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
 
-	wg.Wait()
+// loadHotkeyBindings lê as hotkeys do config.json. Se o arquivo for inválido,
+// o erro é registrado e as hotkeys padrão são usadas.
+func loadHotkeyBindings() []config.Binding {
+	cfg, err := config.Load(config.Path())
+	if err != nil {
+		log.Println("Erro na configuração, usando hotkeys padrão:", err)
+	}
+	bindings, err := cfg.HotkeyBindings()
+	if err != nil {
+		log.Println("Erro nas hotkeys configuradas:", err)
+	}
+	return bindings
 }
 
 var clientCmd *exec.Cmd
 
-func runClient(command string, arg string) {
+func runClient(command string, args ...string) {
 	log.Println("Processo não está rodando. Iniciando...")
 
-	clientCmd = exec.Command(command, arg)
-	if err := clientCmd.Start(); err != nil {
+	cmd := exec.Command(command, args...)
+	if err := cmd.Start(); err != nil {
 		log.Printf("Erro ao iniciar o comando: %v", err)
 		return
 	}
 	runState.Lock()
 	runState.running = true
+	clientCmd = cmd
 	runState.Unlock()
 
 	log.Println("Comando executado com sucesso.")
 
 	// Espera pelo processo do cliente terminar para atualizar o estado.
 	go func() {
-		clientCmd.Wait()
+		cmd.Wait()
 		runState.Lock()
-		runState.running = false
-		clientCmd = nil
+		if clientCmd == cmd {
+			runState.running = false
+			clientCmd = nil
+		}
 		runState.Unlock()
 		log.Println("Cliente encerrado.")
 	}()
 }
 
-func executeTerminal(arg string) error {
+func executeTerminal(args ...string) error {
 	exePath, err := os.Executable()
 	if err != nil {
 		return fmt.Errorf("erro ao achar executavel. Erro: %v", err)
@@ -184,7 +290,7 @@ func executeTerminal(arg string) error {
 	log.Printf("Tentando executar o binário em %v", clientBinaryPath)
 	fmt.Println("ClientBinary = ", clientBinaryPath)
 	if _, err := os.Stat(clientBinaryPath); err == nil {
-		runClient(clientBinaryPath, arg)
+		runClient(clientBinaryPath, args...)
 	} else {
 		log.Printf("Binário do cliente não encontrado em %s: %v", clientBinaryPath, err)
 	}
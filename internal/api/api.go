@@ -0,0 +1,555 @@
+// Package api expõe as notas por HTTP/JSON para plugins de editor e scripts.
+// O server só a liga quando configurada e a escuta apenas em loopback; cada
+// requisição precisa do token do config.json em "Authorization: Bearer".
+package api
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gustavo-silva98/adnotes/internal/reminder"
+	"github.com/gustavo-silva98/adnotes/internal/repository/file"
+)
+
+// Limites das requisições.
+const (
+	DefaultLimit = 50
+	MaxLimit     = 500
+	maxBodyBytes = 1 << 20
+)
+
+// Note é a representação JSON de uma nota. Snippet só vem na busca: é o
+// trecho do texto em volta dos termos encontrados.
+type Note struct {
+	ID         int        `json:"id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	Title      string     `json:"title"`
+	Text       string     `json:"text"`
+	NotebookID int        `json:"notebook_id"`
+	Tags       []string   `json:"tags"`
+	Reminder   *time.Time `json:"reminder,omitempty"`
+	Repeat     int        `json:"repeat_seconds,omitempty"`
+	Pinned     bool       `json:"pinned"`
+	Snippet    string     `json:"snippet,omitempty"`
+}
+
+// noteInput é o corpo aceito na criação e na edição de notas. Remind usa as
+// mesmas expressões do TUI, como "in 2h" ou "every monday". Title ausente na
+// edição mantém o título atual; "" volta a derivá-lo do texto.
+type noteInput struct {
+	Title      *string  `json:"title"`
+	Text       string   `json:"text"`
+	NotebookID int      `json:"notebook_id"`
+	Tags       []string `json:"tags"`
+	Remind     string   `json:"remind"`
+}
+
+type tagInput struct {
+	Tag string `json:"tag"`
+}
+
+type reminderInput struct {
+	Remind string `json:"remind"`
+}
+
+// Error é o corpo das respostas de erro.
+type Error struct {
+	Error string `json:"error"`
+}
+
+// httpError carrega o status HTTP de um erro de validação.
+type httpError struct {
+	status int
+	msg    string
+}
+
+func (e httpError) Error() string { return e.msg }
+
+func badRequest(format string, args ...any) error {
+	return httpError{status: http.StatusBadRequest, msg: fmt.Sprintf(format, args...)}
+}
+
+// Handler atende a API sobre um file.Writer. Now é injetável para testes de lembrete.
+type Handler struct {
+	DB    file.Writer
+	Token string
+	Now   func() time.Time
+	mux   *http.ServeMux
+}
+
+// New cria o Handler com as rotas registradas.
+func New(db file.Writer, token string) *Handler {
+	h := &Handler{DB: db, Token: token, Now: time.Now, mux: http.NewServeMux()}
+
+	h.handle("GET /api/notes", h.listNotes)
+	h.handle("POST /api/notes", h.createNote)
+	h.handle("GET /api/notes/{id}", h.getNote)
+	h.handle("PUT /api/notes/{id}", h.updateNote)
+	h.handle("DELETE /api/notes/{id}", h.deleteNote)
+	h.handle("POST /api/notes/{id}/tags", h.addTag)
+	h.handle("DELETE /api/notes/{id}/tags/{tag}", h.removeTag)
+	h.handle("PUT /api/notes/{id}/reminder", h.setReminder)
+	h.handle("DELETE /api/notes/{id}/reminder", h.clearReminder)
+	h.handle("GET /api/search", h.search)
+	h.handle("GET /api/tags", h.listTags)
+	h.handle("GET /api/notebooks", h.listNotebooks)
+	h.handle("GET /api/reminders", h.dueReminders)
+	h.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "rota não encontrada")
+	})
+	return h
+}
+
+// handle registra uma rota que devolve (corpo, status) ou erro.
+func (h *Handler) handle(pattern string, fn func(r *http.Request) (any, int, error)) {
+	h.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+		body, status, err := fn(r)
+		if err != nil {
+			status := errorStatus(err)
+			if status == http.StatusServiceUnavailable {
+				w.Header().Set("Retry-After", "1")
+			}
+			writeError(w, status, err.Error())
+			return
+		}
+		if body == nil {
+			w.WriteHeader(status)
+			return
+		}
+		writeJSON(w, status, body)
+	})
+}
+
+// ServeHTTP recusa conexões que não venham do loopback e requisições sem o token.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if ip := net.ParseIP(host); err != nil || ip == nil || !ip.IsLoopback() {
+		writeError(w, http.StatusForbidden, "acesso permitido apenas via loopback")
+		return
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || h.Token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.Token)) != 1 {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="pulsenote"`)
+		writeError(w, http.StatusUnauthorized, "token ausente ou inválido")
+		return
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, Error{Error: msg})
+}
+
+// errorStatus traduz os erros do repositório para códigos HTTP.
+func errorStatus(err error) int {
+	var he httpError
+	switch {
+	case errors.As(err, &he):
+		return he.status
+	case errors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound
+	case errors.Is(err, file.ErrInvalidTag),
+		errors.Is(err, reminder.ErrInvalid),
+		errors.Is(err, file.ErrSortField),
+		errors.Is(err, file.ErrNotebookName):
+		return http.StatusBadRequest
+	case file.IsBusy(err):
+		// Outro processo segura o banco; o cliente pode tentar de novo.
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func decodeBody(r *http.Request, v any) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return badRequest("corpo JSON inválido: %v", err)
+	}
+	return nil
+}
+
+func pathID(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		return 0, badRequest("id de nota inválido %q", r.PathValue("id"))
+	}
+	return id, nil
+}
+
+// queryInt lê um parâmetro inteiro não negativo, usando def quando ausente.
+func queryInt(r *http.Request, name string, def int) (int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		return 0, badRequest("parâmetro %v inválido %q", name, raw)
+	}
+	return n, nil
+}
+
+// queryFilter monta o filtro a partir dos parâmetros tag e notebook (id).
+func queryFilter(r *http.Request) (file.NoteFilter, error) {
+	notebookID, err := queryInt(r, "notebook", 0)
+	if err != nil {
+		return file.NoteFilter{}, err
+	}
+	return file.NoteFilter{Tag: r.URL.Query().Get("tag"), NotebookID: notebookID}, nil
+}
+
+func newNote(n file.Note) Note {
+	out := Note{
+		ID:         n.ID,
+		CreatedAt:  time.Unix(n.CreatedAt, 0).UTC(),
+		UpdatedAt:  time.Unix(n.UpdatedAt, 0).UTC(),
+		Title:      n.Title,
+		Text:       n.NoteText,
+		NotebookID: n.NotebookID,
+		Tags:       n.Tags,
+		Repeat:     n.PlusReminder,
+		Pinned:     n.Pinned,
+	}
+	if out.Tags == nil {
+		out.Tags = []string{}
+	}
+	if n.Reminder > 0 {
+		at := time.Unix(int64(n.Reminder), 0).UTC()
+		out.Reminder = &at
+	}
+	return out
+}
+
+// searchNotes converte os resultados da busca, mantendo a ordem de relevância.
+func searchNotes(results []file.SearchResult) []Note {
+	out := make([]Note, 0, len(results))
+	for _, r := range results {
+		note := newNote(r.Note)
+		note.Snippet = file.StripSnippet(r.Snippet)
+		out = append(out, note)
+	}
+	return out
+}
+
+func (h *Handler) listNotes(r *http.Request) (any, int, error) {
+	limit, err := queryInt(r, "limit", DefaultLimit)
+	if err != nil {
+		return nil, 0, err
+	}
+	if limit == 0 || limit > MaxLimit {
+		limit = MaxLimit
+	}
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil {
+		return nil, 0, err
+	}
+	filter, err := queryFilter(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	opts := file.QueryOptions{NoteFilter: filter, Limit: limit, Offset: offset}
+	if opts.Sort, err = file.ParseSortField(r.URL.Query().Get("sort")); err != nil {
+		return nil, 0, err
+	}
+	switch order := r.URL.Query().Get("order"); order {
+	case "", "desc":
+	case "asc":
+		opts.Direction = file.SortAsc
+	default:
+		return nil, 0, badRequest("parâmetro order inválido %q (use asc ou desc)", order)
+	}
+	notes, err := h.DB.QueryNotes(r.Context(), opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	out := make([]Note, 0, len(notes))
+	for _, n := range notes {
+		out = append(out, newNote(n))
+	}
+	return out, http.StatusOK, nil
+}
+
+func (h *Handler) createNote(r *http.Request) (any, int, error) {
+	var in noteInput
+	if err := decodeBody(r, &in); err != nil {
+		return nil, 0, err
+	}
+	if strings.TrimSpace(in.Text) == "" {
+		return nil, 0, badRequest("text é obrigatório")
+	}
+	now := h.Now()
+	note := file.Note{CreatedAt: now.Unix(), NoteText: in.Text, NotebookID: in.NotebookID, Tags: in.Tags}
+	if in.Title != nil {
+		note.Title = *in.Title
+	}
+	if in.Remind != "" {
+		schedule, err := reminder.Parse(in.Remind, now)
+		if err != nil {
+			return nil, 0, err
+		}
+		note.Reminder = schedule.Reminder()
+		note.PlusReminder = schedule.PlusReminder()
+	}
+	if in.NotebookID != 0 {
+		if err := h.checkNotebook(r, in.NotebookID); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	// A nota e as tags são gravadas juntas: se algo falhar, nada fica no banco.
+	id, err := h.DB.InsertNote(&note, r.Context())
+	if err != nil {
+		return nil, 0, err
+	}
+	return h.noteResponse(r, int(id), http.StatusCreated)
+}
+
+// checkNotebook confirma que o caderno existe antes de gravar a nota nele.
+func (h *Handler) checkNotebook(r *http.Request, notebookID int) error {
+	notebooks, err := h.DB.ListNotebooks(r.Context())
+	if err != nil {
+		return err
+	}
+	for _, nb := range notebooks {
+		if nb.ID == notebookID {
+			return nil
+		}
+	}
+	return badRequest("caderno %d não encontrado", notebookID)
+}
+
+func (h *Handler) noteResponse(r *http.Request, id int, status int) (any, int, error) {
+	note, err := h.DB.GetNote(r.Context(), id)
+	if err != nil {
+		return nil, 0, err
+	}
+	return newNote(note), status, nil
+}
+
+func (h *Handler) getNote(r *http.Request) (any, int, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	return h.noteResponse(r, id, http.StatusOK)
+}
+
+// updateNote troca o texto e o título da nota e, se notebook_id vier preenchido, move a
+// nota. Lembrete e tags manuais são mantidos; use as rotas próprias para eles.
+func (h *Handler) updateNote(r *http.Request) (any, int, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	var in noteInput
+	if err := decodeBody(r, &in); err != nil {
+		return nil, 0, err
+	}
+	if strings.TrimSpace(in.Text) == "" {
+		return nil, 0, badRequest("text é obrigatório")
+	}
+	if len(in.Tags) > 0 || in.Remind != "" {
+		return nil, 0, badRequest("tags e remind não são aceitos na edição; use /tags e /reminder")
+	}
+
+	note, err := h.DB.GetNote(r.Context(), id)
+	if err != nil {
+		return nil, 0, err
+	}
+	if in.NotebookID != 0 && in.NotebookID != note.NotebookID {
+		if err := h.checkNotebook(r, in.NotebookID); err != nil {
+			return nil, 0, err
+		}
+		if _, err := h.DB.MoveNote(r.Context(), id, in.NotebookID); err != nil {
+			return nil, 0, err
+		}
+	}
+	title := note.Title
+	if in.Title != nil {
+		title = strings.TrimSpace(*in.Title)
+	}
+	if in.Text != note.NoteText || title != note.Title {
+		note.NoteText = in.Text
+		note.Title = title
+		note.UpdatedAt = h.Now().Unix()
+		n, err := h.DB.UpdateEditNoteRepository(r.Context(), note)
+		if err != nil {
+			return nil, 0, err
+		}
+		if n == 0 {
+			return nil, 0, sql.ErrNoRows
+		}
+	}
+	return h.noteResponse(r, id, http.StatusOK)
+}
+
+func (h *Handler) deleteNote(r *http.Request) (any, int, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	n, err := h.DB.DeleteNoteRepository(r.Context(), id)
+	if err != nil {
+		return nil, 0, err
+	}
+	if n == 0 {
+		return nil, 0, sql.ErrNoRows
+	}
+	return nil, http.StatusNoContent, nil
+}
+
+func (h *Handler) addTag(r *http.Request) (any, int, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	var in tagInput
+	if err := decodeBody(r, &in); err != nil {
+		return nil, 0, err
+	}
+	if err := h.DB.AddTag(r.Context(), id, in.Tag); err != nil {
+		return nil, 0, err
+	}
+	return h.noteResponse(r, id, http.StatusOK)
+}
+
+func (h *Handler) removeTag(r *http.Request) (any, int, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	n, err := h.DB.RemoveTag(r.Context(), id, r.PathValue("tag"))
+	if err != nil {
+		return nil, 0, err
+	}
+	if n == 0 {
+		return nil, 0, sql.ErrNoRows
+	}
+	return h.noteResponse(r, id, http.StatusOK)
+}
+
+func (h *Handler) setReminder(r *http.Request) (any, int, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	var in reminderInput
+	if err := decodeBody(r, &in); err != nil {
+		return nil, 0, err
+	}
+	schedule, err := reminder.Parse(in.Remind, h.Now())
+	if err != nil {
+		return nil, 0, err
+	}
+	if schedule.IsZero() {
+		return nil, 0, badRequest("remind é obrigatório; use DELETE para remover o lembrete")
+	}
+	return h.saveReminder(r, id, schedule.Reminder(), schedule.PlusReminder())
+}
+
+func (h *Handler) clearReminder(r *http.Request) (any, int, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	return h.saveReminder(r, id, 0, 0)
+}
+
+func (h *Handler) saveReminder(r *http.Request, id, at, every int) (any, int, error) {
+	n, err := h.DB.SetReminder(r.Context(), id, at, every)
+	if err != nil {
+		return nil, 0, err
+	}
+	if n == 0 {
+		return nil, 0, sql.ErrNoRows
+	}
+	return h.noteResponse(r, id, http.StatusOK)
+}
+
+func (h *Handler) search(r *http.Request) (any, int, error) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		return nil, 0, badRequest("parâmetro q é obrigatório")
+	}
+	filter, err := queryFilter(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	search := h.DB.FullSearchNote
+	switch mode := r.URL.Query().Get("mode"); mode {
+	case "", "exact":
+	case "fuzzy":
+		search = h.DB.FuzzySearchNote
+	default:
+		return nil, 0, badRequest("parâmetro mode inválido %q (use exact ou fuzzy)", mode)
+	}
+	results, err := search(r.Context(), q, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return searchNotes(results), http.StatusOK, nil
+}
+
+func (h *Handler) listTags(r *http.Request) (any, int, error) {
+	tags, err := h.DB.ListTags(r.Context())
+	if err != nil {
+		return nil, 0, err
+	}
+	type tagJSON struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+	out := make([]tagJSON, 0, len(tags))
+	for _, t := range tags {
+		out = append(out, tagJSON{Name: t.Name, Count: t.Count})
+	}
+	return out, http.StatusOK, nil
+}
+
+func (h *Handler) listNotebooks(r *http.Request) (any, int, error) {
+	notebooks, err := h.DB.ListNotebooks(r.Context())
+	if err != nil {
+		return nil, 0, err
+	}
+	type notebookJSON struct {
+		ID       int    `json:"id"`
+		Name     string `json:"name"`
+		ParentID int    `json:"parent_id,omitempty"`
+	}
+	out := make([]notebookJSON, 0, len(notebooks))
+	for _, nb := range notebooks {
+		out = append(out, notebookJSON{ID: nb.ID, Name: nb.Name, ParentID: nb.ParentID})
+	}
+	return out, http.StatusOK, nil
+}
+
+// dueReminders lista os lembretes já vencidos, como o agendador do server os vê.
+func (h *Handler) dueReminders(r *http.Request) (any, int, error) {
+	due, err := h.DB.DueReminders(r.Context(), h.Now().Unix())
+	if err != nil {
+		return nil, 0, err
+	}
+	out := make([]Note, 0, len(due))
+	for _, n := range due {
+		out = append(out, newNote(n))
+	}
+	return out, http.StatusOK, nil
+}
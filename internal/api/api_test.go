@@ -0,0 +1,408 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gustavo-silva98/adnotes/internal/api"
+	"github.com/gustavo-silva98/adnotes/internal/repository/file"
+)
+
+const token = "token-de-teste"
+
+var fixedNow = time.Date(2025, 10, 20, 12, 0, 0, 0, time.UTC)
+
+type testAPI struct {
+	t   *testing.T
+	srv *httptest.Server
+	db  *file.SqliteHandler
+}
+
+// newTestAPI sobe a API sobre um banco temporário com as notas informadas.
+func newTestAPI(t *testing.T, texts ...string) *testAPI {
+	t.Helper()
+	ctx := context.Background()
+	db, err := file.InitDB(filepath.Join(t.TempDir(), "banco.db"), ctx)
+	if err != nil {
+		t.Fatalf("Erro ao criar banco: %v", err)
+	}
+	t.Cleanup(func() { db.DB.Close() })
+	for i, text := range texts {
+		if _, err := db.InsertNote(&file.Note{CreatedAt: int64(1700000000 + i), NoteText: text}, ctx); err != nil {
+			t.Fatalf("Erro ao inserir nota: %v", err)
+		}
+	}
+
+	h := api.New(db, token)
+	h.Now = func() time.Time { return fixedNow }
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	return &testAPI{t: t, srv: srv, db: db}
+}
+
+// do envia a requisição autenticada e decodifica a resposta em out, se não for nil.
+func (a *testAPI) do(method, path string, body any, out any) int {
+	a.t.Helper()
+	var reader io.Reader
+	if body != nil {
+		data, _ := json.Marshal(body)
+		reader = bytes.NewReader(data)
+	}
+	req, _ := http.NewRequest(method, a.srv.URL+path, reader)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		a.t.Fatalf("%v %v: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			a.t.Fatalf("%v %v: resposta não é JSON: %v", method, path, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestAuthRequired(t *testing.T) {
+	a := newTestAPI(t)
+	for _, auth := range []string{"", "Bearer errado", token} {
+		req, _ := http.NewRequest("GET", a.srv.URL+"/api/notes", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var body api.Error
+		json.NewDecoder(resp.Body).Decode(&body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized || body.Error == "" {
+			t.Errorf("Authorization %q: status %d, erro %q", auth, resp.StatusCode, body.Error)
+		}
+	}
+}
+
+func TestRejectsNonLoopback(t *testing.T) {
+	h := api.New(nil, token)
+	req := httptest.NewRequest("GET", "/api/notes", nil)
+	req.RemoteAddr = "192.168.0.10:5000"
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Status %d, esperado %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+func TestListNotes(t *testing.T) {
+	a := newTestAPI(t, "primeira #a", "segunda", "terceira #a")
+
+	var notes []api.Note
+	if code := a.do("GET", "/api/notes?tag=a", nil, &notes); code != http.StatusOK {
+		t.Fatalf("Status %d", code)
+	}
+	if len(notes) != 2 || notes[0].ID != 3 || notes[1].ID != 1 {
+		t.Errorf("Lista filtrada inesperada: %+v", notes)
+	}
+
+	if code := a.do("GET", "/api/notes?limit=1&offset=1", nil, &notes); code != http.StatusOK {
+		t.Fatalf("Status %d", code)
+	}
+	if len(notes) != 1 || notes[0].ID != 2 || len(notes[0].Tags) != 0 {
+		t.Errorf("Paginação inesperada: %+v", notes)
+	}
+
+	if code := a.do("GET", "/api/notes?sort=text&order=asc", nil, &notes); code != http.StatusOK {
+		t.Fatalf("Status %d", code)
+	}
+	if len(notes) != 3 || notes[0].ID != 1 || notes[1].ID != 2 || notes[2].ID != 3 {
+		t.Errorf("Ordem alfabética inesperada: %+v", notes)
+	}
+
+	for _, query := range []string{"limit=abc", "sort=id", "order=up"} {
+		var apiErr api.Error
+		if code := a.do("GET", "/api/notes?"+query, nil, &apiErr); code != http.StatusBadRequest {
+			t.Errorf("%v: status %d", query, code)
+		}
+	}
+}
+
+func TestCreateAndGetNote(t *testing.T) {
+	a := newTestAPI(t)
+
+	var created api.Note
+	body := map[string]any{"text": "nova #inline", "tags": []string{"Extra"}, "remind": "in 2h"}
+	if code := a.do("POST", "/api/notes", body, &created); code != http.StatusCreated {
+		t.Fatalf("Status %d", code)
+	}
+	if created.ID == 0 || created.NotebookID != file.InboxNotebookID || len(created.Tags) != 2 {
+		t.Errorf("Nota criada inesperada: %+v", created)
+	}
+	if created.Reminder == nil || !created.Reminder.Equal(fixedNow.Add(2*time.Hour)) {
+		t.Errorf("Lembrete inesperado: %v", created.Reminder)
+	}
+
+	var got api.Note
+	if code := a.do("GET", "/api/notes/1", nil, &got); code != http.StatusOK || got.Text != "nova #inline" {
+		t.Errorf("GET: status %d, nota %+v", code, got)
+	}
+
+	var apiErr api.Error
+	cases := []struct {
+		body   any
+		status int
+	}{
+		{map[string]any{"text": " "}, http.StatusBadRequest},
+		{map[string]any{"text": "x", "notebook_id": 99}, http.StatusBadRequest},
+		{map[string]any{"text": "x", "tags": []string{"com espaço"}}, http.StatusBadRequest},
+		{map[string]any{"text": "x", "remind": "quando der"}, http.StatusBadRequest},
+		{map[string]any{"texto": "campo errado"}, http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		if code := a.do("POST", "/api/notes", c.body, &apiErr); code != c.status {
+			t.Errorf("POST %v: status %d, esperado %d", c.body, code, c.status)
+		}
+	}
+	if code := a.do("GET", "/api/notes/42", nil, &apiErr); code != http.StatusNotFound {
+		t.Errorf("Nota inexistente: status %d", code)
+	}
+
+	var notes []api.Note
+	if code := a.do("GET", "/api/notes", nil, &notes); code != http.StatusOK || len(notes) != 1 {
+		t.Errorf("Requisições recusadas não deveriam gravar notas: %+v", notes)
+	}
+}
+
+func TestUpdateNote(t *testing.T) {
+	a := newTestAPI(t, "antes #velha")
+	ctx := context.Background()
+	notebookID, err := a.db.CreateNotebook(ctx, "Trabalho", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var note api.Note
+	body := map[string]any{"text": "depois #nova", "notebook_id": notebookID}
+	if code := a.do("PUT", "/api/notes/1", body, &note); code != http.StatusOK {
+		t.Fatalf("Status %d", code)
+	}
+	if note.Text != "depois #nova" || note.NotebookID != int(notebookID) || len(note.Tags) != 1 || note.Tags[0] != "nova" {
+		t.Errorf("Nota editada inesperada: %+v", note)
+	}
+	if !note.UpdatedAt.Equal(fixedNow) {
+		t.Errorf("Edição deveria atualizar a hora: %v", note.UpdatedAt)
+	}
+
+	for _, tc := range []struct {
+		body map[string]any
+		want string
+	}{
+		{map[string]any{"text": "depois #nova", "title": " Ata "}, "Ata"},
+		{map[string]any{"text": "outro texto"}, "Ata"},
+		{map[string]any{"text": "outro texto", "title": ""}, ""},
+	} {
+		var titled api.Note
+		if code := a.do("PUT", "/api/notes/1", tc.body, &titled); code != http.StatusOK {
+			t.Fatalf("Status %d", code)
+		}
+		if titled.Title != tc.want {
+			t.Errorf("%v: título %q, esperado %q", tc.body, titled.Title, tc.want)
+		}
+	}
+
+	var apiErr api.Error
+	if code := a.do("PUT", "/api/notes/9", map[string]any{"text": "x"}, &apiErr); code != http.StatusNotFound {
+		t.Errorf("Nota inexistente: status %d", code)
+	}
+	if code := a.do("PUT", "/api/notes/1", map[string]any{"text": "x", "remind": "in 1h"}, &apiErr); code != http.StatusBadRequest {
+		t.Errorf("remind na edição: status %d", code)
+	}
+}
+
+func TestDeleteNote(t *testing.T) {
+	a := newTestAPI(t, "apagar")
+
+	if code := a.do("DELETE", "/api/notes/1", nil, nil); code != http.StatusNoContent {
+		t.Fatalf("Status %d", code)
+	}
+	var apiErr api.Error
+	if code := a.do("DELETE", "/api/notes/1", nil, &apiErr); code != http.StatusNotFound {
+		t.Errorf("Segunda remoção: status %d", code)
+	}
+	if code := a.do("DELETE", "/api/notes/abc", nil, &apiErr); code != http.StatusBadRequest {
+		t.Errorf("id inválido: status %d", code)
+	}
+
+	// A nota na lixeira não aceita edições.
+	edits := []struct {
+		method, path string
+		body         any
+	}{
+		{"PUT", "/api/notes/1", map[string]any{"text": "editada"}},
+		{"POST", "/api/notes/1/tags", map[string]any{"tag": "nova"}},
+		{"PUT", "/api/notes/1/reminder", map[string]any{"remind": "in 1h"}},
+	}
+	for _, e := range edits {
+		if code := a.do(e.method, e.path, e.body, &apiErr); code != http.StatusNotFound {
+			t.Errorf("%v %v na lixeira: status %d", e.method, e.path, code)
+		}
+	}
+}
+
+func TestTagsEndpoints(t *testing.T) {
+	a := newTestAPI(t, "nota #a", "outra #a #b")
+
+	var note api.Note
+	if code := a.do("POST", "/api/notes/1/tags", map[string]string{"tag": "C"}, &note); code != http.StatusOK {
+		t.Fatalf("Status %d", code)
+	}
+	if len(note.Tags) != 2 {
+		t.Errorf("Tag não adicionada: %+v", note)
+	}
+	if code := a.do("DELETE", "/api/notes/1/tags/c", nil, &note); code != http.StatusOK || len(note.Tags) != 1 {
+		t.Errorf("Remoção de tag: status %d, nota %+v", code, note)
+	}
+
+	var apiErr api.Error
+	if code := a.do("DELETE", "/api/notes/1/tags/c", nil, &apiErr); code != http.StatusNotFound {
+		t.Errorf("Tag já removida: status %d", code)
+	}
+	if code := a.do("POST", "/api/notes/7/tags", map[string]string{"tag": "x"}, &apiErr); code != http.StatusNotFound {
+		t.Errorf("Nota inexistente: status %d", code)
+	}
+
+	var tags []struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+	if code := a.do("GET", "/api/tags", nil, &tags); code != http.StatusOK {
+		t.Fatalf("Status %d", code)
+	}
+	if len(tags) != 2 || tags[0].Name != "a" || tags[0].Count != 2 {
+		t.Errorf("Tags inesperadas: %+v", tags)
+	}
+}
+
+func TestNotebooksEndpoint(t *testing.T) {
+	a := newTestAPI(t)
+	if _, err := a.db.CreateNotebook(context.Background(), "Trabalho", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	var notebooks []struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	if code := a.do("GET", "/api/notebooks", nil, &notebooks); code != http.StatusOK {
+		t.Fatalf("Status %d", code)
+	}
+	if len(notebooks) != 2 || notebooks[0].Name != "Inbox" {
+		t.Errorf("Cadernos inesperados: %+v", notebooks)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	a := newTestAPI(t, "comprar pão #casa", "comprar livro", "pão de queijo")
+
+	var notes []api.Note
+	if code := a.do("GET", "/api/search?q=comprar", nil, &notes); code != http.StatusOK {
+		t.Fatalf("Status %d", code)
+	}
+	if len(notes) != 2 || notes[0].ID != 2 {
+		t.Errorf("Busca inesperada: %+v", notes)
+	}
+	if len(notes) > 0 && notes[0].Snippet != "comprar livro" {
+		t.Errorf("Trecho inesperado: %q", notes[0].Snippet)
+	}
+	if code := a.do("GET", "/api/search?q=p%C3%A3o&tag=casa", nil, &notes); code != http.StatusOK || len(notes) != 1 {
+		t.Errorf("Busca com tag: status %d, notas %+v", code, notes)
+	}
+
+	if code := a.do("GET", "/api/search?q=comprra&mode=fuzzy", nil, &notes); code != http.StatusOK || len(notes) != 2 {
+		t.Errorf("Busca aproximada: status %d, notas %+v", code, notes)
+	}
+
+	var apiErr api.Error
+	if code := a.do("GET", "/api/search?q=comprar&mode=x", nil, &apiErr); code != http.StatusBadRequest {
+		t.Errorf("Busca com mode inválido: status %d", code)
+	}
+	if code := a.do("GET", "/api/search", nil, &apiErr); code != http.StatusBadRequest {
+		t.Errorf("Busca sem q: status %d", code)
+	}
+}
+
+func TestReminderEndpoints(t *testing.T) {
+	a := newTestAPI(t, "lembrar")
+
+	var note api.Note
+	if code := a.do("PUT", "/api/notes/1/reminder", map[string]string{"remind": "every monday"}, &note); code != http.StatusOK {
+		t.Fatalf("Status %d", code)
+	}
+	if note.Reminder == nil || note.Repeat != int((7*24*time.Hour).Seconds()) {
+		t.Errorf("Lembrete semanal inesperado: %+v", note)
+	}
+
+	var due []api.Note
+	if code := a.do("GET", "/api/reminders", nil, &due); code != http.StatusOK || len(due) != 0 {
+		t.Errorf("Lembrete futuro não deveria estar vencido: status %d, %+v", code, due)
+	}
+	if _, err := a.db.SetReminder(context.Background(), 1, int(fixedNow.Add(-time.Minute).Unix()), 0); err != nil {
+		t.Fatal(err)
+	}
+	if code := a.do("GET", "/api/reminders", nil, &due); code != http.StatusOK || len(due) != 1 {
+		t.Errorf("Lembrete vencido: status %d, %+v", code, due)
+	}
+
+	var cleared api.Note
+	if code := a.do("DELETE", "/api/notes/1/reminder", nil, &cleared); code != http.StatusOK || cleared.Reminder != nil {
+		t.Errorf("Remoção do lembrete: status %d, nota %+v", code, cleared)
+	}
+
+	var apiErr api.Error
+	if code := a.do("PUT", "/api/notes/1/reminder", map[string]string{"remind": "off"}, &apiErr); code != http.StatusBadRequest {
+		t.Errorf("remind off: status %d", code)
+	}
+	if code := a.do("PUT", "/api/notes/5/reminder", map[string]string{"remind": "in 1h"}, &apiErr); code != http.StatusNotFound {
+		t.Errorf("Nota inexistente: status %d", code)
+	}
+}
+
+func TestBusyDatabaseIsRetryable(t *testing.T) {
+	a := newTestAPI(t)
+	ctx := context.Background()
+	// Sem espera, a disputa pelo lock aparece na hora em vez de após 5s.
+	if _, err := a.db.DB.Exec(`PRAGMA busy_timeout = 0`); err != nil {
+		t.Fatal(err)
+	}
+	other, err := file.InitDB(a.db.DbPath, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.DB.Close()
+	tx, err := other.DB.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	var apiErr api.Error
+	if code := a.do("POST", "/api/notes", map[string]any{"text": "nova"}, &apiErr); code != http.StatusServiceUnavailable {
+		t.Errorf("Banco ocupado: status %d (%v), esperado 503", code, apiErr.Error)
+	}
+}
+
+func TestUnknownRoute(t *testing.T) {
+	a := newTestAPI(t)
+	var apiErr api.Error
+	if code := a.do("GET", "/api/desconhecida", nil, &apiErr); code != http.StatusNotFound || apiErr.Error == "" {
+		t.Errorf("Rota desconhecida: status %d, erro %q", code, apiErr.Error)
+	}
+}
@@ -0,0 +1,196 @@
+// Package cli implementa os subcomandos não interativos do client, pensados
+// para scripts: leem e gravam direto no banco, sem abrir o TUI.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/gustavo-silva98/adnotes/internal/repository/file"
+	"github.com/gustavo-silva98/adnotes/internal/transfer"
+)
+
+// Códigos de saída dos subcomandos.
+const (
+	ExitOK    = 0
+	ExitError = 1
+	ExitUsage = 2
+	// ExitNotFound indica que a nota pedida (show, edit, rm) não existe.
+	ExitNotFound = 3
+)
+
+// Env são as dependências de um subcomando, substituídas nos testes.
+type Env struct {
+	DBPath string
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// errUsage marca erros de uso, que terminam com ExitUsage.
+var errUsage = errors.New("uso incorreto")
+
+// errNotFound marca notas inexistentes, que terminam com ExitNotFound.
+var errNotFound = errors.New("não encontrada")
+
+func usageErrorf(format string, args ...any) error {
+	return fmt.Errorf("%w: %v", errUsage, fmt.Sprintf(format, args...))
+}
+
+type command struct {
+	summary string
+	run     func(ctx context.Context, env Env, args []string) error
+}
+
+var commands = map[string]command{
+	"add":    {summary: "cria uma nota com o texto dos argumentos ou da entrada padrão", run: runAdd},
+	"list":   {summary: "lista as notas mais recentes", run: runList},
+	"show":   {summary: "mostra o texto de uma nota", run: runShow},
+	"edit":   {summary: "edita uma nota no $EDITOR", run: runEdit},
+	"rm":     {summary: "move notas para a lixeira", run: runRemove},
+	"search": {summary: "busca notas no índice full-text", run: runSearch},
+	"export": {summary: "exporta notas para Markdown, JSON ou CSV", run: runExport},
+	"import": {summary: "importa notas de arquivos .md/.txt, JSON ou CSV", run: runImport},
+}
+
+// DefaultDBPath retorna o banco usado pelos subcomandos: PULSENOTE_DB, se
+// definido, ou o banco padrão na pasta de dados. A pasta é resolvida a partir
+// do executável, não da pasta atual, para que scripts, hooks e cron rodados em
+// qualquer lugar usem o mesmo banco do server e do TUI.
+func DefaultDBPath() string {
+	if path := os.Getenv("PULSENOTE_DB"); path != "" {
+		return path
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return file.DataPath("banco.db")
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	return filepath.Join(filepath.Dir(exe), file.DataPath("banco.db"))
+}
+
+// IsCommand indica se name é um subcomando da CLI.
+func IsCommand(name string) bool {
+	_, ok := commands[name]
+	return ok || name == "help"
+}
+
+// Run executa o subcomando em args[0] e retorna o código de saída.
+func Run(ctx context.Context, args []string, env Env) int {
+	if len(args) == 0 || args[0] == "help" {
+		printUsage(env.Stderr)
+		if len(args) == 0 {
+			return ExitUsage
+		}
+		return ExitOK
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(env.Stderr, "client: subcomando desconhecido %q\n", args[0])
+		printUsage(env.Stderr)
+		return ExitUsage
+	}
+
+	err := cmd.run(ctx, env, args[1:])
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return ExitOK
+	case errors.Is(err, errUsage):
+		fmt.Fprintf(env.Stderr, "client %v: %v\n", args[0], err)
+		return ExitUsage
+	case errors.Is(err, errNotFound):
+		fmt.Fprintf(env.Stderr, "client %v: %v\n", args[0], err)
+		return ExitNotFound
+	default:
+		fmt.Fprintf(env.Stderr, "client %v: %v\n", args[0], err)
+		return ExitError
+	}
+}
+
+func printUsage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "Uso: client <subcomando> [opções]")
+	fmt.Fprintln(w)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-8v %v\n", name, commands[name].summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Use \"client <subcomando> -h\" para ver as opções.")
+}
+
+// newFlagSet cria o conjunto de flags do subcomando, com erros em env.Stderr.
+func newFlagSet(env Env, name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(env.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(env.Stderr, "Uso: client %v %v\n", name, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags aceita flags antes ou depois dos argumentos, como em
+// "show 12 -json", e retorna os argumentos. Tudo após "--" é argumento. Erros
+// de parse viram erros de uso; a mensagem já foi impressa pelo flag.FlagSet.
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, usageErrorf("%v", err)
+		}
+		// fs.Parse consome o "--"; o que sobra depois dele é só argumento.
+		terminated := len(args) > len(fs.Args()) && args[len(args)-len(fs.Args())-1] == "--"
+		if fs.NArg() == 0 || terminated {
+			return append(positional, fs.Args()...), nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+func openDB(ctx context.Context, env Env) (*file.SqliteHandler, error) {
+	db, err := file.InitDB(env.DBPath, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir banco %v: %v", env.DBPath, err)
+	}
+	return db, nil
+}
+
+// resolveFilter monta o filtro a partir das flags -tag e -notebook.
+func resolveFilter(ctx context.Context, db file.Writer, tag, notebook string) (file.NoteFilter, error) {
+	filter := file.NoteFilter{Tag: tag}
+	if tag != "" {
+		if _, err := file.NormalizeTag(tag); err != nil {
+			return filter, usageErrorf("%v", err)
+		}
+	}
+	if notebook != "" {
+		notebooks, err := db.ListNotebooks(ctx)
+		if err != nil {
+			return filter, err
+		}
+		id, ok := transfer.FindNotebook(notebooks, notebook)
+		if !ok {
+			return filter, usageErrorf("caderno %q não encontrado", notebook)
+		}
+		filter.NotebookID = id
+	}
+	return filter, nil
+}
@@ -0,0 +1,271 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/gustavo-silva98/adnotes/internal/cli"
+	"github.com/gustavo-silva98/adnotes/internal/repository/file"
+)
+
+// newEnv cria um banco temporário com as notas informadas.
+func newEnv(t *testing.T, texts ...string) (cli.Env, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "banco.db")
+	db, err := file.InitDB(dbPath, ctx)
+	if err != nil {
+		t.Fatalf("Erro ao criar banco: %v", err)
+	}
+	for i, text := range texts {
+		if _, err := db.InsertNote(&file.Note{CreatedAt: int64(1700000000 + i), NoteText: text}, ctx); err != nil {
+			t.Fatalf("Erro ao inserir nota: %v", err)
+		}
+	}
+	db.DB.Close()
+
+	var stdout, stderr bytes.Buffer
+	return cli.Env{DBPath: dbPath, Stdin: &bytes.Buffer{}, Stdout: &stdout, Stderr: &stderr}, &stdout, &stderr
+}
+
+func run(env cli.Env, args ...string) int {
+	return cli.Run(context.Background(), args, env)
+}
+
+func TestDefaultDBPathIgnoresWorkingDirectory(t *testing.T) {
+	t.Setenv("PULSENOTE_DB", "")
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skipf("Executável indisponível: %v", err)
+	}
+	exe, _ = filepath.EvalSymlinks(exe)
+	want := filepath.Join(filepath.Dir(exe), file.DataPath("banco.db"))
+
+	t.Chdir(t.TempDir())
+	if got := cli.DefaultDBPath(); got != want || !filepath.IsAbs(got) {
+		t.Errorf("DefaultDBPath() = %q, esperado %q", got, want)
+	}
+
+	t.Setenv("PULSENOTE_DB", "/tmp/outro.db")
+	if got := cli.DefaultDBPath(); got != "/tmp/outro.db" {
+		t.Errorf("PULSENOTE_DB deveria prevalecer: %q", got)
+	}
+}
+
+func TestExportJSONToStdout(t *testing.T) {
+	env, stdout, stderr := newEnv(t, "primeira #a", "segunda")
+
+	if code := run(env, "export", "-format", "json", "-tag", "a"); code != cli.ExitOK {
+		t.Fatalf("Código %d, stderr: %v", code, stderr)
+	}
+	var notes []map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &notes); err != nil {
+		t.Fatalf("Saída não é JSON válido: %v\n%v", err, stdout)
+	}
+	if len(notes) != 1 || notes[0]["text"] != "primeira #a" {
+		t.Errorf("Exportação filtrada inesperada: %v", notes)
+	}
+}
+
+func TestExportMarkdownDirectory(t *testing.T) {
+	env, _, stderr := newEnv(t, "uma", "outra")
+	dir := filepath.Join(t.TempDir(), "md")
+
+	if code := run(env, "export", "-format", "md", "-out", dir); code != cli.ExitOK {
+		t.Fatalf("Código %d, stderr: %v", code, stderr)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Errorf("Esperados 2 arquivos, obtidos %d", len(entries))
+	}
+}
+
+func TestExportUsageErrors(t *testing.T) {
+	env, _, _ := newEnv(t)
+	cases := [][]string{
+		{"export", "-format", "xml"},
+		{"export", "-format", "md"},
+		{"export", "-notebook", "Inexistente"},
+		{"export", "-nao-existe"},
+		{"desconhecido"},
+	}
+	for _, args := range cases {
+		if code := run(env, args...); code != cli.ExitUsage {
+			t.Errorf("%v: código %d, esperado %d", args, code, cli.ExitUsage)
+		}
+	}
+}
+
+func TestImportReportsErrorsAndContinues(t *testing.T) {
+	env, stdout, stderr := newEnv(t)
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "boa.md"), []byte("nota importada"), 0644)
+	os.WriteFile(filepath.Join(dir, "ruim.md"), []byte("---\nsem fechamento"), 0644)
+
+	if code := run(env, "import", dir); code != cli.ExitError {
+		t.Errorf("Código %d, esperado %d", code, cli.ExitError)
+	}
+	if !strings.Contains(stdout.String(), "1 importadas, 0 duplicadas, 1 com erro") {
+		t.Errorf("Resumo inesperado: %q", stdout)
+	}
+	if !strings.Contains(stderr.String(), "ruim.md") {
+		t.Errorf("Erro deveria citar o arquivo: %q", stderr)
+	}
+	if code := run(env, "import"); code != cli.ExitUsage {
+		t.Errorf("Sem caminho: código %d, esperado %d", code, cli.ExitUsage)
+	}
+}
+
+func TestAddFromArgsAndStdin(t *testing.T) {
+	env, stdout, stderr := newEnv(t)
+
+	if code := run(env, "add", "-tag", "extra", "nota", "pelos", "argumentos", "#inline"); code != cli.ExitOK {
+		t.Fatalf("Código %d, stderr: %v", code, stderr)
+	}
+	if got := strings.TrimSpace(stdout.String()); got != "1" {
+		t.Errorf("add deveria imprimir o id, obtido %q", got)
+	}
+
+	env.Stdin = strings.NewReader("linha 1\nlinha 2\n")
+	stdout.Reset()
+	if code := run(env, "add", "-remind", "in 2h", "-title", "Título, com vírgula"); code != cli.ExitOK {
+		t.Fatalf("Código %d, stderr: %v", code, stderr)
+	}
+
+	stdout.Reset()
+	if code := run(env, "list", "-json"); code != cli.ExitOK {
+		t.Fatalf("Código %d, stderr: %v", code, stderr)
+	}
+	var notes []map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &notes); err != nil {
+		t.Fatalf("Saída não é JSON válido: %v\n%v", err, stdout)
+	}
+	if len(notes) != 2 {
+		t.Fatalf("Esperadas 2 notas, obtidas %d", len(notes))
+	}
+	if notes[0]["text"] != "linha 1\nlinha 2" || notes[0]["reminder"] == nil || notes[0]["title"] != "Título, com vírgula" {
+		t.Errorf("Nota da entrada padrão inesperada: %v", notes[0])
+	}
+	if tags, _ := notes[1]["tags"].([]any); len(tags) != 2 {
+		t.Errorf("Esperadas tags extra e inline, obtidas %v", notes[1]["tags"])
+	}
+
+	env.Stdin = strings.NewReader("  \n")
+	if code := run(env, "add"); code != cli.ExitUsage {
+		t.Errorf("Nota vazia: código %d, esperado %d", code, cli.ExitUsage)
+	}
+}
+
+func TestListAndSearchPlainText(t *testing.T) {
+	env, stdout, stderr := newEnv(t, "comprar pão #casa", "reunião de projeto\ncom detalhes", "outra #casa")
+
+	if code := run(env, "list", "-limit", "2"); code != cli.ExitOK {
+		t.Fatalf("Código %d, stderr: %v", code, stderr)
+	}
+	lines := strings.Split(strings.TrimRight(stdout.String(), "\n"), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "3\t") || !strings.HasSuffix(lines[0], "\toutra #casa\t#casa") {
+		t.Errorf("list inesperado: %q", lines)
+	}
+	if !strings.HasSuffix(lines[1], "\treunião de projeto\t") {
+		t.Errorf("list deveria mostrar só a primeira linha: %q", lines[1])
+	}
+
+	stdout.Reset()
+	if code := run(env, "list", "-sort", "text", "-asc", "-limit", "1"); code != cli.ExitOK {
+		t.Fatalf("Código %d, stderr: %v", code, stderr)
+	}
+	if got := stdout.String(); !strings.HasPrefix(got, "1\t") {
+		t.Errorf("list -sort text deveria começar por \"comprar\": %q", got)
+	}
+	if code := run(env, "list", "-sort", "id"); code != cli.ExitUsage {
+		t.Errorf("-sort inválido: código %d, esperado %d", code, cli.ExitUsage)
+	}
+
+	stdout.Reset()
+	if code := run(env, "search", "pão", "-tag", "casa"); code != cli.ExitOK {
+		t.Fatalf("Código %d, stderr: %v", code, stderr)
+	}
+	if got := stdout.String(); !strings.HasPrefix(got, "1\t") || strings.Count(got, "\n") != 1 {
+		t.Errorf("search inesperado: %q", got)
+	}
+
+	stdout.Reset()
+	if code := run(env, "search", "-fuzzy", "reuniao", "projteo"); code != cli.ExitOK {
+		t.Fatalf("Código %d, stderr: %v", code, stderr)
+	}
+	if got := stdout.String(); !strings.HasPrefix(got, "2\t") || strings.Count(got, "\n") != 1 {
+		t.Errorf("search -fuzzy inesperado: %q", got)
+	}
+
+	stdout.Reset()
+	if code := run(env, "search", `"pão`); code != cli.ExitOK {
+		t.Fatalf("Código %d, stderr: %v", code, stderr)
+	}
+	if !strings.Contains(stderr.String(), "aspas sem fechamento") || strings.Count(stdout.String(), "\n") != 1 {
+		t.Errorf("busca inválida deveria avisar e buscar o texto literal: stdout %q, stderr %q", stdout, stderr)
+	}
+
+	if code := run(env, "search"); code != cli.ExitUsage {
+		t.Errorf("search sem consulta: código %d, esperado %d", code, cli.ExitUsage)
+	}
+}
+
+func TestShowAndRemove(t *testing.T) {
+	env, stdout, stderr := newEnv(t, "texto completo\nsegunda linha", "outra")
+
+	if code := run(env, "show", "1"); code != cli.ExitOK {
+		t.Fatalf("Código %d, stderr: %v", code, stderr)
+	}
+	if stdout.String() != "texto completo\nsegunda linha\n" {
+		t.Errorf("show inesperado: %q", stdout)
+	}
+
+	if code := run(env, "rm", "1", "99"); code != cli.ExitNotFound {
+		t.Errorf("rm com id inexistente: código %d, esperado %d", code, cli.ExitNotFound)
+	}
+	if code := run(env, "show", "1"); code != cli.ExitNotFound {
+		t.Errorf("Nota 1 deveria ter sido removida, código %d", code)
+	}
+	if code := run(env, "show", "2"); code != cli.ExitOK {
+		t.Errorf("Nota 2 não deveria ser removida, código %d", code)
+	}
+	if code := run(env, "show", "abc"); code != cli.ExitUsage {
+		t.Errorf("id inválido: código %d, esperado %d", code, cli.ExitUsage)
+	}
+}
+
+func TestEditUsesEditor(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("editor de teste é um script sh")
+	}
+	env, stdout, stderr := newEnv(t, "antes #velha")
+	script := filepath.Join(t.TempDir(), "editor.sh")
+	os.WriteFile(script, []byte("#!/bin/sh\nprintf 'depois #nova\\n' > \"$1\"\n"), 0755)
+	t.Setenv("EDITOR", script)
+
+	if code := run(env, "edit", "1"); code != cli.ExitOK {
+		t.Fatalf("Código %d, stderr: %v", code, stderr)
+	}
+	if code := run(env, "show", "-json", "1"); code != cli.ExitOK {
+		t.Fatalf("Código %d, stderr: %v", code, stderr)
+	}
+	var note map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &note); err != nil {
+		t.Fatalf("Saída não é JSON válido: %v\n%v", err, stdout)
+	}
+	if note["text"] != "depois #nova" || fmt.Sprint(note["tags"]) != "[nova]" {
+		t.Errorf("Nota editada inesperada: %v", note)
+	}
+
+	t.Setenv("EDITOR", "false")
+	if code := run(env, "edit", "1"); code != cli.ExitError {
+		t.Errorf("Editor com erro: código %d, esperado %d", code, cli.ExitError)
+	}
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/gustavo-silva98/adnotes/internal/transfer"
+)
+
+func runExport(ctx context.Context, env Env, args []string) error {
+	fs := newFlagSet(env, "export", "[-format md|json|csv] [-out caminho] [-tag tag] [-notebook caderno]")
+	format := fs.String("format", "json", "formato de saída: md, json ou csv")
+	out := fs.String("out", "", "arquivo de saída (json/csv, padrão stdout) ou diretório (md, obrigatório)")
+	tag := fs.String("tag", "", "exporta só as notas com esta tag")
+	notebook := fs.String("notebook", "", "exporta só as notas deste caderno, como \"Trabalho/Projeto X\"")
+	rest, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return usageErrorf("argumento inesperado %q", rest[0])
+	}
+	if *format == "md" && *out == "" {
+		return usageErrorf("-format md exige -out com o diretório de destino")
+	}
+	if *format != "md" && *format != "json" && *format != "csv" {
+		return usageErrorf("formato desconhecido %q", *format)
+	}
+
+	db, err := openDB(ctx, env)
+	if err != nil {
+		return err
+	}
+	defer db.DB.Close()
+
+	filter, err := resolveFilter(ctx, db, *tag, *notebook)
+	if err != nil {
+		return err
+	}
+
+	var exp transfer.Exporter
+	var outFile *os.File
+	if *format == "md" {
+		if exp, err = transfer.NewMarkdownExporter(*out); err != nil {
+			return err
+		}
+	} else {
+		w := env.Stdout
+		if *out != "" {
+			if outFile, err = os.Create(*out); err != nil {
+				return err
+			}
+			defer outFile.Close()
+			w = outFile
+		}
+		if *format == "json" {
+			exp = transfer.NewJSONExporter(w)
+		} else {
+			exp = transfer.NewCSVExporter(w)
+		}
+	}
+
+	count, err := transfer.Export(ctx, db, filter, exp)
+	if err != nil {
+		return err
+	}
+	if outFile != nil {
+		if err := outFile.Close(); err != nil {
+			return err
+		}
+	}
+	if *out != "" {
+		fmt.Fprintf(env.Stderr, "%d notas exportadas para %v\n", count, *out)
+	}
+	return nil
+}
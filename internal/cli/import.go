@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gustavo-silva98/adnotes/internal/transfer"
+)
+
+func runImport(ctx context.Context, env Env, args []string) error {
+	fs := newFlagSet(env, "import", "<diretório|arquivo.md|arquivo.txt|export.json|export.csv>...")
+	paths, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return usageErrorf("informe ao menos um diretório ou arquivo")
+	}
+
+	db, err := openDB(ctx, env)
+	if err != nil {
+		return err
+	}
+	defer db.DB.Close()
+
+	im := transfer.NewImporter(db)
+	var total transfer.ImportResult
+	for _, path := range paths {
+		result, err := im.Import(ctx, path)
+		if err != nil {
+			result.Errors = append(result.Errors, transfer.FileError{Path: path, Err: err})
+		}
+		total.Imported += result.Imported
+		total.Duplicates += result.Duplicates
+		total.Errors = append(total.Errors, result.Errors...)
+	}
+
+	for _, e := range total.Errors {
+		fmt.Fprintf(env.Stderr, "erro: %v\n", e)
+	}
+	fmt.Fprintf(env.Stdout, "%d importadas, %d duplicadas, %d com erro\n", total.Imported, total.Duplicates, len(total.Errors))
+	if len(total.Errors) > 0 {
+		return fmt.Errorf("%d erros na importação", len(total.Errors))
+	}
+	return nil
+}
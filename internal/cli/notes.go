@@ -0,0 +1,409 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gustavo-silva98/adnotes/internal/notetitle"
+	"github.com/gustavo-silva98/adnotes/internal/reminder"
+	"github.com/gustavo-silva98/adnotes/internal/repository/file"
+	"github.com/gustavo-silva98/adnotes/internal/transfer"
+)
+
+// titleWidth é a largura máxima, em colunas, do título exibido por list e search.
+const titleWidth = 60
+
+// tagList acumula as ocorrências repetidas de -tag.
+type tagList []string
+
+func (t *tagList) String() string { return strings.Join(*t, ",") }
+
+func (t *tagList) Set(value string) error {
+	tag, err := file.NormalizeTag(value)
+	if err != nil {
+		return err
+	}
+	*t = append(*t, tag)
+	return nil
+}
+
+// parseID converte o argumento de id de nota, tratando valores inválidos como uso incorreto.
+func parseID(arg string) (int, error) {
+	id, err := strconv.Atoi(arg)
+	if err != nil || id <= 0 {
+		return 0, usageErrorf("id de nota inválido %q", arg)
+	}
+	return id, nil
+}
+
+func notFound(id int) error {
+	return fmt.Errorf("%w: nota %d", errNotFound, id)
+}
+
+func runAdd(ctx context.Context, env Env, args []string) error {
+	fs := newFlagSet(env, "add", "[-title título] [-tag tag]... [-notebook caderno] [-remind expressão] [texto...]")
+	title := fs.String("title", "", "título da nota (padrão: primeiro cabeçalho ou linha do texto)")
+	var tags tagList
+	fs.Var(&tags, "tag", "adiciona a tag à nota (pode repetir)")
+	notebook := fs.String("notebook", "", "caderno da nota, como \"Trabalho/Projeto X\" (padrão Inbox)")
+	remind := fs.String("remind", "", "lembrete, como \"in 2h\" ou \"every monday\"")
+	rest, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	// Sem texto nos argumentos, a nota vem da entrada padrão, como em "echo ... | client add".
+	text := strings.Join(rest, " ")
+	if len(rest) == 0 {
+		data, err := io.ReadAll(env.Stdin)
+		if err != nil {
+			return fmt.Errorf("erro ao ler a entrada: %v", err)
+		}
+		text = strings.TrimRight(string(data), "\r\n")
+	}
+	if strings.TrimSpace(text) == "" {
+		return usageErrorf("nota vazia")
+	}
+
+	now := time.Now()
+	note := file.Note{CreatedAt: now.Unix(), Title: *title, NoteText: text, Tags: tags}
+	if *remind != "" {
+		schedule, err := reminder.Parse(*remind, now)
+		if err != nil {
+			return usageErrorf("%v", err)
+		}
+		note.Reminder = schedule.Reminder()
+		note.PlusReminder = schedule.PlusReminder()
+	}
+
+	db, err := openDB(ctx, env)
+	if err != nil {
+		return err
+	}
+	defer db.DB.Close()
+
+	filter, err := resolveFilter(ctx, db, "", *notebook)
+	if err != nil {
+		return err
+	}
+	note.NotebookID = filter.NotebookID
+
+	// A nota e as tags são gravadas juntas; repetir o comando após uma falha
+	// não duplica a nota.
+	id, err := db.InsertNote(&note, ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao salvar nota: %v", err)
+	}
+	fmt.Fprintln(env.Stdout, id)
+	return nil
+}
+
+func runList(ctx context.Context, env Env, args []string) error {
+	fs := newFlagSet(env, "list", "[-limit n] [-tag tag] [-notebook caderno] [-sort campo] [-asc] [-json]")
+	limit := fs.Int("limit", 20, "número máximo de notas, das mais novas para as mais antigas (0 lista todas)")
+	tag := fs.String("tag", "", "lista só as notas com esta tag")
+	notebook := fs.String("notebook", "", "lista só as notas deste caderno")
+	sortBy := fs.String("sort", "created", "ordena por created, updated ou text")
+	asc := fs.Bool("asc", false, "inverte a ordem, do mais antigo (ou de A a Z) em diante")
+	asJSON := fs.Bool("json", false, "imprime um array JSON em vez de texto")
+	rest, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return usageErrorf("argumento inesperado %q", rest[0])
+	}
+	if *limit < 0 {
+		return usageErrorf("-limit não pode ser negativo")
+	}
+	field, err := file.ParseSortField(*sortBy)
+	if err != nil {
+		return usageErrorf("%v", err)
+	}
+
+	db, err := openDB(ctx, env)
+	if err != nil {
+		return err
+	}
+	defer db.DB.Close()
+
+	filter, err := resolveFilter(ctx, db, *tag, *notebook)
+	if err != nil {
+		return err
+	}
+	opts := file.QueryOptions{NoteFilter: filter, Sort: field, Limit: *limit}
+	if *asc {
+		opts.Direction = file.SortAsc
+	}
+	notes, err := db.QueryNotes(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("erro ao listar notas: %v", err)
+	}
+	return printNotes(ctx, env, db, notes, *asJSON)
+}
+
+func runSearch(ctx context.Context, env Env, args []string) error {
+	fs := newFlagSet(env, "search", "[-tag tag] [-notebook caderno] [-fuzzy] [-json] <consulta>")
+	tag := fs.String("tag", "", "busca só nas notas com esta tag")
+	notebook := fs.String("notebook", "", "busca só nas notas deste caderno")
+	fuzzy := fs.Bool("fuzzy", false, "tolera erros de digitação, ordenando por semelhança")
+	asJSON := fs.Bool("json", false, "imprime um array JSON em vez de texto")
+	rest, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	query := strings.TrimSpace(strings.Join(rest, " "))
+	if query == "" {
+		return usageErrorf("informe o texto a buscar")
+	}
+
+	db, err := openDB(ctx, env)
+	if err != nil {
+		return err
+	}
+	defer db.DB.Close()
+
+	filter, err := resolveFilter(ctx, db, *tag, *notebook)
+	if err != nil {
+		return err
+	}
+	if _, err := file.ParseSearchQuery(query, time.Now()); err != nil {
+		fmt.Fprintf(env.Stderr, "client search: %v; buscando o texto literal\n", err)
+	}
+	search := db.FullSearchNote
+	if *fuzzy {
+		search = db.FuzzySearchNote
+	}
+	results, err := search(ctx, query, filter)
+	if err != nil {
+		if errors.Is(err, file.ErrInvalidTag) {
+			return usageErrorf("%v", err)
+		}
+		return fmt.Errorf("erro na busca: %v", err)
+	}
+	return printNotes(ctx, env, db, resultNotes(results), *asJSON)
+}
+
+// resultNotes devolve as notas da busca, mantendo a ordem de relevância.
+func resultNotes(results []file.SearchResult) []file.Note {
+	notes := make([]file.Note, len(results))
+	for i, r := range results {
+		notes[i] = r.Note
+	}
+	return notes
+}
+
+// printNotes imprime as notas na ordem recebida: uma por linha (id, data,
+// primeira linha e tags separados por tab) ou como array JSON.
+func printNotes(ctx context.Context, env Env, db file.Writer, notes []file.Note, asJSON bool) error {
+	if !asJSON {
+		for _, note := range notes {
+			tags := make([]string, len(note.Tags))
+			for i, tag := range note.Tags {
+				tags[i] = "#" + tag
+			}
+			fmt.Fprintf(env.Stdout, "%d\t%v\t%v\t%v\n",
+				note.ID,
+				time.Unix(note.UpdatedAt, 0).Format("2006-01-02 15:04"),
+				notetitle.Of(note.Title, note.NoteText, titleWidth),
+				strings.Join(tags, " "),
+			)
+		}
+		return nil
+	}
+
+	notebooks, err := db.ListNotebooks(ctx)
+	if err != nil {
+		return err
+	}
+	paths := transfer.NotebookPaths(notebooks)
+	records := make([]transfer.Record, 0, len(notes))
+	for _, note := range notes {
+		records = append(records, transfer.NewRecord(note, paths))
+	}
+	return writeJSON(env.Stdout, records)
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func runShow(ctx context.Context, env Env, args []string) error {
+	fs := newFlagSet(env, "show", "[-json] <id>")
+	asJSON := fs.Bool("json", false, "imprime a nota como objeto JSON, com tags, caderno e lembrete")
+	rest, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		return usageErrorf("informe um id de nota")
+	}
+	id, err := parseID(rest[0])
+	if err != nil {
+		return err
+	}
+
+	db, err := openDB(ctx, env)
+	if err != nil {
+		return err
+	}
+	defer db.DB.Close()
+
+	note, err := db.GetNote(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return notFound(id)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !*asJSON {
+		fmt.Fprintln(env.Stdout, note.NoteText)
+		return nil
+	}
+	notebooks, err := db.ListNotebooks(ctx)
+	if err != nil {
+		return err
+	}
+	return writeJSON(env.Stdout, transfer.NewRecord(note, transfer.NotebookPaths(notebooks)))
+}
+
+func runRemove(ctx context.Context, env Env, args []string) error {
+	fs := newFlagSet(env, "rm", "<id>...")
+	rest, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) == 0 {
+		return usageErrorf("informe ao menos um id de nota")
+	}
+	ids := make([]int, len(rest))
+	for i, arg := range rest {
+		if ids[i], err = parseID(arg); err != nil {
+			return err
+		}
+	}
+
+	db, err := openDB(ctx, env)
+	if err != nil {
+		return err
+	}
+	defer db.DB.Close()
+
+	// Ids inexistentes não impedem a remoção dos demais; o erro sai no final.
+	var missing []string
+	for _, id := range ids {
+		n, err := db.DeleteNoteRepository(ctx, id)
+		if err != nil {
+			return fmt.Errorf("erro ao remover nota %d: %v", id, err)
+		}
+		if n == 0 {
+			missing = append(missing, strconv.Itoa(id))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: notas %v", errNotFound, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func runEdit(ctx context.Context, env Env, args []string) error {
+	fs := newFlagSet(env, "edit", "<id>")
+	rest, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		return usageErrorf("informe um id de nota")
+	}
+	id, err := parseID(rest[0])
+	if err != nil {
+		return err
+	}
+
+	db, err := openDB(ctx, env)
+	if err != nil {
+		return err
+	}
+	defer db.DB.Close()
+
+	note, err := db.GetNote(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return notFound(id)
+	}
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "pulsenote-edit")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, fmt.Sprintf("nota-%d.md", id))
+	if err := os.WriteFile(path, []byte(note.NoteText+"\n"), 0600); err != nil {
+		return err
+	}
+
+	if err := editorCommand(ctx, env, path).Run(); err != nil {
+		return fmt.Errorf("editor terminou com erro, nota não alterada: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	text := strings.TrimRight(string(data), "\r\n")
+	if strings.TrimSpace(text) == "" {
+		return fmt.Errorf("texto vazio, nota não alterada (use rm para remover)")
+	}
+	if text == note.NoteText {
+		fmt.Fprintln(env.Stderr, "nota sem alterações")
+		return nil
+	}
+
+	note.NoteText = text
+	note.UpdatedAt = time.Now().Unix()
+	if _, err := db.UpdateEditNoteRepository(ctx, note); err != nil {
+		return fmt.Errorf("erro ao salvar nota: %v", err)
+	}
+	return nil
+}
+
+// editorCommand abre path no editor de $EDITOR (ou $VISUAL). O valor passa
+// pelo shell para aceitar argumentos, como "code --wait".
+func editorCommand(ctx context.Context, env Env, path string) *exec.Cmd {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = os.Getenv("VISUAL")
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		if editor == "" {
+			editor = "notepad"
+		}
+		cmd = exec.CommandContext(ctx, "cmd", "/C", editor+` "`+path+`"`)
+	} else {
+		if editor == "" {
+			editor = "vi"
+		}
+		cmd = exec.CommandContext(ctx, "sh", "-c", editor+` "$1"`, "sh", path)
+	}
+	cmd.Stdin = env.Stdin
+	cmd.Stdout = env.Stdout
+	cmd.Stderr = env.Stderr
+	return cmd
+}
@@ -20,23 +20,103 @@ type KeyMap struct {
 	No         key.Binding
 	Delete     key.Binding
 	FullSearch key.Binding
+	Snooze     key.Binding
+	Dismiss    key.Binding
+	Reminder   key.Binding
+	TagFilter  key.Binding
+	Notebooks  key.Binding
+	MoveNote   key.Binding
+	Preview    key.Binding
+	History    key.Binding
+	Trash      key.Binding
+	Undo       key.Binding
+	Sort       key.Binding
+	SortDir    key.Binding
+	Filter     key.Binding
+	Pin        key.Binding
+	Title      key.Binding
+	Fuzzy      key.Binding
+	SaveSearch key.Binding
+}
+
+// bind cria um binding cujo texto de ajuda é gerado a partir das próprias
+// teclas, para que a ajuda nunca divirja do que está realmente configurado.
+func bind(desc string, keys ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(HelpKey(keys), desc))
 }
 
 var Default = KeyMap{
-	Save:       key.NewBinding(key.WithKeys("ctrl+s"), key.WithHelp("ctrl+s", "Save and Quit")),
-	Esc:        key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "unfocus textarea")),
-	Up:         key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("^k", "Move Up")),
-	Down:       key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("^j", "Move Up")),
-	Help:       key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "toggle help")),
-	Quit:       key.NewBinding(key.WithKeys("ctrl+q", "esc", "ctrl+q"), key.WithHelp("Ctrl+q", "quit")),
-	Read:       key.NewBinding(key.WithKeys("ctrl+r"), key.WithHelp("ctrl+r", "Read notes")),
-	PageBack:   key.NewBinding(key.WithKeys("alt+left"), key.WithHelp("alt+left", "Page Back")),
-	PageFoward: key.NewBinding(key.WithKeys("alt+right"), key.WithHelp("alt+right", "Page Foward")),
-	Enter:      key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "Enter Note")),
-	Yes:        key.NewBinding(key.WithKeys("y", "Y"), key.WithHelp("y", "Yes")),
-	No:         key.NewBinding(key.WithKeys("n", "N"), key.WithHelp("n", "No")),
-	Delete:     key.NewBinding(key.WithKeys("ctrl+d"), key.WithHelp("ctrl+d", "Delete Note")),
-	FullSearch: key.NewBinding(key.WithKeys("ctrl+a"), key.WithHelp("alt+s", "Advanced Search")),
+	Save:       bind("Save and Quit", "ctrl+s"),
+	Esc:        bind("Unfocus Textarea", "esc"),
+	Up:         bind("Move Up", "up", "k"),
+	Down:       bind("Move Down", "down", "j"),
+	Help:       bind("Toggle Help", "?"),
+	Quit:       bind("Quit", "ctrl+q"),
+	Read:       bind("Read Notes", "ctrl+r"),
+	Back:       bind("Back", "esc"),
+	PageBack:   bind("Insert Note", "alt+left"),
+	PageFoward: bind("Page Foward", "alt+right"),
+	Enter:      bind("Edit Note", "enter"),
+	Yes:        bind("Yes", "y", "Y"),
+	No:         bind("No", "n", "N"),
+	Delete:     bind("Delete Note", "ctrl+d"),
+	FullSearch: bind("Advanced Search", "ctrl+a"),
+	Snooze:     bind("Snooze", "s", "S"),
+	Dismiss:    bind("Dismiss", "d", "D"),
+	Reminder:   bind("Set Reminder", "ctrl+t"),
+	TagFilter:  bind("Filter Tag", "ctrl+g"),
+	Notebooks:  bind("Notebooks", "ctrl+n"),
+	MoveNote:   bind("Move Note", "alt+m"),
+	Preview:    bind("Raw/Preview", "ctrl+p"),
+	History:    bind("History", "alt+h"),
+	Trash:      bind("Trash", "alt+t"),
+	Undo:       bind("Undo Delete", "ctrl+z"),
+	Sort:       bind("Sort By", "alt+o"),
+	SortDir:    bind("Reverse Sort", "alt+d"),
+	Filter:     bind("Filter", "ctrl+f"),
+	Pin:        bind("Pin/Unpin", "alt+p"),
+	Title:      bind("Edit Title", "ctrl+e"),
+	Fuzzy:      bind("Exact/Fuzzy", "alt+f"),
+	SaveSearch: bind("Save Search", "alt+s"),
+}
+
+// Named expõe os bindings pelo nome do campo, usado no arquivo de keymap e
+// nas mensagens de validação.
+func (k *KeyMap) Named() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"Save":       &k.Save,
+		"Up":         &k.Up,
+		"Down":       &k.Down,
+		"Quit":       &k.Quit,
+		"Esc":        &k.Esc,
+		"Help":       &k.Help,
+		"Read":       &k.Read,
+		"Back":       &k.Back,
+		"PageBack":   &k.PageBack,
+		"PageFoward": &k.PageFoward,
+		"Enter":      &k.Enter,
+		"Yes":        &k.Yes,
+		"No":         &k.No,
+		"Delete":     &k.Delete,
+		"FullSearch": &k.FullSearch,
+		"Snooze":     &k.Snooze,
+		"Dismiss":    &k.Dismiss,
+		"Reminder":   &k.Reminder,
+		"TagFilter":  &k.TagFilter,
+		"Notebooks":  &k.Notebooks,
+		"MoveNote":   &k.MoveNote,
+		"Preview":    &k.Preview,
+		"History":    &k.History,
+		"Trash":      &k.Trash,
+		"Undo":       &k.Undo,
+		"Sort":       &k.Sort,
+		"SortDir":    &k.SortDir,
+		"Filter":     &k.Filter,
+		"Pin":        &k.Pin,
+		"Title":      &k.Title,
+		"Fuzzy":      &k.Fuzzy,
+		"SaveSearch": &k.SaveSearch,
+	}
 }
 
 func (k KeyMap) ShortHelp() []key.Binding {
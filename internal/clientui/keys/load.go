@@ -0,0 +1,84 @@
+package keys
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+var keyNames = map[string]string{
+	"ctrl":  "Ctrl",
+	"alt":   "Alt",
+	"shift": "Shift",
+	"enter": "Enter",
+	"esc":   "Esc",
+	"tab":   "Tab",
+	"space": "Space",
+	"up":    "↑",
+	"down":  "↓",
+	"left":  "←",
+	"right": "→",
+}
+
+// HelpKey formata as teclas de um binding para a ajuda, como "Ctrl + s" ou "↑/k".
+// Variações só de maiúscula/minúscula ("y" e "Y") aparecem uma única vez.
+func HelpKey(keys []string) string {
+	var out []string
+	seen := map[string]bool{}
+	for _, k := range keys {
+		if seen[strings.ToLower(k)] {
+			continue
+		}
+		seen[strings.ToLower(k)] = true
+
+		parts := strings.Split(k, "+")
+		for i, p := range parts {
+			if name, ok := keyNames[p]; ok {
+				parts[i] = name
+			}
+		}
+		out = append(out, strings.Join(parts, " + "))
+	}
+	return strings.Join(out, "/")
+}
+
+// Load aplica sobre base as sobrescritas do arquivo em path, um objeto JSON
+// que associa o nome do binding às suas teclas:
+//
+//	{"FullSearch": ["ctrl+f"], "Quit": ["ctrl+q", "ctrl+c"]}
+//
+// A descrição de cada binding é mantida e o texto de ajuda é regerado a partir
+// das novas teclas. Se o arquivo não existir, base é retornado sem alterações.
+func Load(path string, base KeyMap) (KeyMap, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return base, nil
+	}
+	if err != nil {
+		return base, fmt.Errorf("erro ao ler keymap: %v", err)
+	}
+
+	var overrides map[string][]string
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(&overrides); err != nil {
+		return base, fmt.Errorf("erro ao interpretar %v: %v", path, err)
+	}
+
+	km := base
+	named := km.Named()
+	for name, keys := range overrides {
+		b, ok := named[name]
+		if !ok {
+			return base, fmt.Errorf("binding desconhecido em %v: %q", path, name)
+		}
+		if len(keys) == 0 {
+			return base, fmt.Errorf("binding %q sem teclas em %v", name, path)
+		}
+		*b = key.NewBinding(key.WithKeys(keys...), key.WithHelp(HelpKey(keys), b.Help().Desc))
+	}
+	return km, nil
+}
@@ -0,0 +1,82 @@
+package keys_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gustavo-silva98/adnotes/internal/clientui/keys"
+)
+
+func writeKeymap(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "keymap.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Erro ao escrever keymap de teste - %v", err)
+	}
+	return path
+}
+
+func TestDefaultHelpMatchesKeys(t *testing.T) {
+	km := keys.Default
+	for name, b := range km.Named() {
+		if b.Help().Key != keys.HelpKey(b.Keys()) {
+			t.Errorf("Ajuda de %v (%q) diverge das teclas %v", name, b.Help().Key, b.Keys())
+		}
+	}
+}
+
+func TestHelpKey(t *testing.T) {
+	tests := []struct {
+		keys []string
+		want string
+	}{
+		{[]string{"ctrl+s"}, "Ctrl + s"},
+		{[]string{"up", "k"}, "↑/k"},
+		{[]string{"alt+left"}, "Alt + ←"},
+		{[]string{"y", "Y"}, "y"},
+		{[]string{"enter"}, "Enter"},
+	}
+	for _, tt := range tests {
+		if got := keys.HelpKey(tt.keys); got != tt.want {
+			t.Errorf("HelpKey(%v) = %q, esperado %q", tt.keys, got, tt.want)
+		}
+	}
+}
+
+func TestLoadWithoutFile(t *testing.T) {
+	km, err := keys.Load(filepath.Join(t.TempDir(), "nao-existe.json"), keys.Default)
+	if err != nil {
+		t.Fatalf("Keymap inexistente não deveria gerar erro - %v", err)
+	}
+	if km.Save.Help().Key != keys.Default.Save.Help().Key {
+		t.Error("Keymap padrão deveria ser mantido")
+	}
+}
+
+func TestLoadOverridesBinding(t *testing.T) {
+	km, err := keys.Load(writeKeymap(t, `{"FullSearch": ["ctrl+f"]}`), keys.Default)
+	if err != nil {
+		t.Fatalf("Erro ao carregar keymap - %v", err)
+	}
+	if got := km.FullSearch.Keys(); len(got) != 1 || got[0] != "ctrl+f" {
+		t.Errorf("Teclas sobrescritas não aplicadas: %v", got)
+	}
+	if km.FullSearch.Help().Key != "Ctrl + f" {
+		t.Errorf("Ajuda não foi regerada: %q", km.FullSearch.Help().Key)
+	}
+	if km.FullSearch.Help().Desc != keys.Default.FullSearch.Help().Desc {
+		t.Errorf("Descrição deveria ser mantida: %q", km.FullSearch.Help().Desc)
+	}
+	if keys.Default.FullSearch.Keys()[0] != "ctrl+a" {
+		t.Error("Load não pode alterar o keymap base")
+	}
+}
+
+func TestLoadRejectsInvalidFile(t *testing.T) {
+	for _, content := range []string{`{"Teleport": ["ctrl+t"]}`, `{"Save": []}`, `{"Save": "ctrl+s"}`} {
+		if _, err := keys.Load(writeKeymap(t, content), keys.Default); err == nil {
+			t.Errorf("Esperado erro para keymap %v", content)
+		}
+	}
+}
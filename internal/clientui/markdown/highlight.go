@@ -0,0 +1,136 @@
+package markdown
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/muesli/termenv"
+	"golang.org/x/text/unicode/norm"
+)
+
+// O realce usa só o atributo "reverso", que é desligado sem desfazer as cores
+// e os estilos aplicados pelo glamour em volta do termo.
+const (
+	highlightOn  = "\x1b[7m"
+	highlightOff = "\x1b[27m"
+)
+
+// Highlight realça em s as ocorrências de terms que começam uma palavra, sem
+// diferenciar maiúsculas nem acentos, assim como o FTS casa prefixos. s pode ser a saída
+// de Render: as sequências ANSI são copiadas sem alteração. Sem cores no
+// perfil, s volta igual.
+func (r *Renderer) Highlight(s string, terms []string) string {
+	if r.Profile == termenv.Ascii {
+		return s
+	}
+	var words [][]rune
+	for _, term := range terms {
+		if term != "" {
+			words = append(words, []rune(term))
+		}
+	}
+	if len(words) == 0 {
+		return s
+	}
+
+	var b strings.Builder
+	runes := []rune(s)
+	prev := ' '
+	for i := 0; i < len(runes); {
+		if runes[i] == '\x1b' {
+			end := escapeEnd(runes, i)
+			b.WriteString(string(runes[i:end]))
+			i = end
+			continue
+		}
+		if !isWordRune(prev) {
+			if n := matchWord(runes[i:], words); n > 0 {
+				b.WriteString(highlightOn)
+				b.WriteString(string(runes[i : i+n]))
+				b.WriteString(highlightOff)
+				prev = runes[i+n-1]
+				i += n
+				continue
+			}
+		}
+		b.WriteRune(runes[i])
+		prev = runes[i]
+		i++
+	}
+	return b.String()
+}
+
+// HighlightMarked troca os delimitadores start e end, como os do trecho da
+// busca, pelo realce de Highlight. Sem cores no perfil, só os remove.
+func (r *Renderer) HighlightMarked(s, start, end string) string {
+	on, off := highlightOn, highlightOff
+	if r.Profile == termenv.Ascii {
+		on, off = "", ""
+	}
+	return strings.NewReplacer(start, on, end, off).Replace(s)
+}
+
+// matchWord devolve o tamanho, em runas, do termo mais longo que começa em s,
+// ou 0 se nenhum começar.
+func matchWord(s []rune, words [][]rune) int {
+	best := 0
+	for _, word := range words {
+		if len(word) <= best || len(word) > len(s) {
+			continue
+		}
+		match := true
+		for i, r := range word {
+			if foldRune(r) != foldRune(s[i]) {
+				match = false
+				break
+			}
+		}
+		if match {
+			best = len(word)
+		}
+	}
+	return best
+}
+
+// escapeEnd devolve o índice logo após a sequência ANSI que começa em i.
+func escapeEnd(s []rune, i int) int {
+	j := i + 1
+	if j >= len(s) {
+		return j
+	}
+	switch s[j] {
+	case '[':
+		// CSI: termina no primeiro byte final, de '@' a '~'.
+		for j++; j < len(s); j++ {
+			if s[j] >= '@' && s[j] <= '~' {
+				return j + 1
+			}
+		}
+		return j
+	case ']':
+		// OSC: termina em BEL ou em ESC \.
+		for j++; j < len(s); j++ {
+			if s[j] == '\a' {
+				return j + 1
+			}
+			if s[j] == '\x1b' && j+1 < len(s) && s[j+1] == '\\' {
+				return j + 2
+			}
+		}
+		return j
+	}
+	return j + 1
+}
+
+// foldRune reduz a runa à letra base minúscula, para que "Ç" case com "c".
+func foldRune(r rune) rune {
+	if r >= utf8.RuneSelf {
+		r, _ = utf8.DecodeRuneInString(norm.NFD.String(string(r)))
+	}
+	return unicode.ToLower(r)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,65 @@
+// Package markdown renderiza o texto das notas como Markdown para o painel
+// de leitura, usando glamour.
+package markdown
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/muesli/termenv"
+)
+
+// DefaultStyle é o estilo padrão do glamour, que combina com o tema escuro do app.
+const DefaultStyle = "dark"
+
+// Renderer converte notas em texto formatado para o terminal. Criar o
+// renderizador do glamour é caro, então ele é recriado apenas quando a
+// largura muda, e o último resultado fica guardado para os redesenhos.
+type Renderer struct {
+	Style   string
+	Profile termenv.Profile
+
+	width    int
+	term     *glamour.TermRenderer
+	source   string
+	rendered string
+}
+
+// New cria um Renderer com o estilo e o perfil de cores informados.
+func New(style string, profile termenv.Profile) *Renderer {
+	return &Renderer{Style: style, Profile: profile}
+}
+
+// Render retorna text renderizado com quebra de linha em width colunas.
+// Quebras de linha simples são preservadas, já que notas raramente seguem a
+// regra de parágrafos do Markdown.
+func (r *Renderer) Render(text string, width int) (string, error) {
+	if width < 1 {
+		width = 1
+	}
+	if r.term != nil && width == r.width && text == r.source {
+		return r.rendered, nil
+	}
+
+	if r.term == nil || width != r.width {
+		term, err := glamour.NewTermRenderer(
+			glamour.WithStandardStyle(r.Style),
+			glamour.WithColorProfile(r.Profile),
+			glamour.WithWordWrap(width),
+			glamour.WithPreservedNewLines(),
+		)
+		if err != nil {
+			return "", err
+		}
+		r.term = term
+		r.width = width
+	}
+
+	out, err := r.term.Render(text)
+	if err != nil {
+		return "", err
+	}
+	r.source = text
+	r.rendered = strings.Trim(out, "\n")
+	return r.rendered, nil
+}
@@ -0,0 +1,101 @@
+package markdown_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gustavo-silva98/adnotes/internal/clientui/markdown"
+	"github.com/muesli/termenv"
+)
+
+var update = flag.Bool("update", false, "regrava os arquivos .golden")
+
+func TestRenderGolden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "*.md"))
+	if err != nil || len(inputs) == 0 {
+		t.Fatalf("Nenhuma entrada em testdata: %v", err)
+	}
+
+	r := markdown.New(markdown.DefaultStyle, termenv.Ascii)
+	for _, input := range inputs {
+		name := strings.TrimSuffix(filepath.Base(input), ".md")
+		t.Run(name, func(t *testing.T) {
+			src, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatalf("Erro ao ler entrada: %v", err)
+			}
+			got, err := r.Render(string(src), 40)
+			if err != nil {
+				t.Fatalf("Erro ao renderizar: %v", err)
+			}
+
+			golden := filepath.Join("testdata", name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+					t.Fatalf("Erro ao gravar golden: %v", err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("Erro ao ler golden (rode com -update para criar): %v", err)
+			}
+			if got != string(want) {
+				t.Errorf("Renderização diverge de %v:\n--- obtido\n%v\n--- esperado\n%v", golden, got, string(want))
+			}
+		})
+	}
+}
+
+func TestRenderReusesResultAndWrapsToWidth(t *testing.T) {
+	r := markdown.New(markdown.DefaultStyle, termenv.Ascii)
+	text := strings.Repeat("palavra ", 20)
+
+	narrow, err := r.Render(text, 20)
+	if err != nil {
+		t.Fatalf("Erro ao renderizar: %v", err)
+	}
+	for _, line := range strings.Split(narrow, "\n") {
+		if len([]rune(line)) > 20 {
+			t.Errorf("Linha maior que a largura: %q", line)
+		}
+	}
+	again, _ := r.Render(text, 20)
+	if again != narrow {
+		t.Error("Mesmo texto e largura deveriam gerar o mesmo resultado")
+	}
+	wide, _ := r.Render(text, 60)
+	if wide == narrow {
+		t.Error("Mudar a largura deveria renderizar de novo")
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	r := markdown.New(markdown.DefaultStyle, termenv.ANSI)
+
+	got := r.Highlight("\x1b[1mRelatório\x1b[0m do correlato: relat", []string{"relat"})
+	want := "\x1b[1m\x1b[7mRelat\x1b[27mório\x1b[0m do correlato: \x1b[7mrelat\x1b[27m"
+	if got != want {
+		t.Errorf("Highlight = %q, esperado %q", got, want)
+	}
+
+	got = r.Highlight("Anotação e anotacao", []string{"ANOTAÇÃO"})
+	want = "\x1b[7mAnotação\x1b[27m e \x1b[7manotacao\x1b[27m"
+	if got != want {
+		t.Errorf("Highlight sem acentos = %q, esperado %q", got, want)
+	}
+
+	if got := r.HighlightMarked("um \x02termo\x03", "\x02", "\x03"); got != "um \x1b[7mtermo\x1b[27m" {
+		t.Errorf("HighlightMarked = %q", got)
+	}
+
+	plain := markdown.New(markdown.DefaultStyle, termenv.Ascii)
+	if got := plain.Highlight("relatório", []string{"relat"}); got != "relatório" {
+		t.Errorf("Sem cores o texto deveria voltar igual: %q", got)
+	}
+	if got := plain.HighlightMarked("um \x02termo\x03", "\x02", "\x03"); got != "um termo" {
+		t.Errorf("Sem cores os marcadores deveriam sumir: %q", got)
+	}
+}
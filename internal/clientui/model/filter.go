@@ -0,0 +1,123 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ListFilter é o filtro digitado na barra de filtro da leitura, como em
+// `#projeto notebook:"Trabalho/Reuniões" from:2025-01-01 to:2025-01-31`.
+type ListFilter struct {
+	Tag      string
+	Notebook string
+	// From é o início do primeiro dia e To o início do dia seguinte ao último,
+	// para que o intervalo [From, To) inclua o dia informado em "to:".
+	From time.Time
+	To   time.Time
+}
+
+// filterDateLayouts são os formatos aceitos em from: e to:.
+var filterDateLayouts = []string{"2006-01-02", "02/01/2006"}
+
+// ParseListFilter interpreta o texto da barra de filtro. Os termos aceitos são
+// #tag ou tag:nome, notebook:caminho, from:data e to:data; valores com espaço
+// vão entre aspas. Datas são lidas no fuso loc.
+func ParseListFilter(s string, loc *time.Location) (ListFilter, error) {
+	var f ListFilter
+	terms, err := splitFilterTerms(s)
+	if err != nil {
+		return ListFilter{}, err
+	}
+	for _, term := range terms {
+		if tag, ok := strings.CutPrefix(term, "#"); ok {
+			f.Tag = tag
+			continue
+		}
+		name, value, ok := strings.Cut(term, ":")
+		if !ok || value == "" {
+			return ListFilter{}, fmt.Errorf("termo inválido %q (use #tag, notebook:, from: ou to:)", term)
+		}
+		switch strings.ToLower(name) {
+		case "tag":
+			f.Tag = strings.TrimPrefix(value, "#")
+		case "notebook":
+			f.Notebook = value
+		case "from", "to":
+			day, err := parseFilterDate(value, loc)
+			if err != nil {
+				return ListFilter{}, err
+			}
+			if strings.EqualFold(name, "from") {
+				f.From = day
+			} else {
+				f.To = day.AddDate(0, 0, 1)
+			}
+		default:
+			return ListFilter{}, fmt.Errorf("filtro desconhecido %q", name)
+		}
+	}
+	if !f.From.IsZero() && !f.To.IsZero() && !f.From.Before(f.To) {
+		return ListFilter{}, fmt.Errorf("from: deve ser anterior a to:")
+	}
+	return f, nil
+}
+
+// String devolve o filtro na sintaxe da barra, para reabri-la preenchida.
+func (f ListFilter) String() string {
+	var terms []string
+	if f.Tag != "" {
+		terms = append(terms, "#"+f.Tag)
+	}
+	if f.Notebook != "" {
+		notebook := f.Notebook
+		if strings.ContainsAny(notebook, " \"") {
+			notebook = fmt.Sprintf("%q", notebook)
+		}
+		terms = append(terms, "notebook:"+notebook)
+	}
+	if !f.From.IsZero() {
+		terms = append(terms, "from:"+f.From.Format("2006-01-02"))
+	}
+	if !f.To.IsZero() {
+		terms = append(terms, "to:"+f.To.AddDate(0, 0, -1).Format("2006-01-02"))
+	}
+	return strings.Join(terms, " ")
+}
+
+func parseFilterDate(value string, loc *time.Location) (time.Time, error) {
+	for _, layout := range filterDateLayouts {
+		if day, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return day, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("data inválida %q (use AAAA-MM-DD ou DD/MM/AAAA)", value)
+}
+
+// splitFilterTerms separa os termos por espaço, mantendo juntos os trechos
+// entre aspas duplas.
+func splitFilterTerms(s string) ([]string, error) {
+	var terms []string
+	var term strings.Builder
+	quoted := false
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ' ' && !quoted:
+			if term.Len() > 0 {
+				terms = append(terms, term.String())
+				term.Reset()
+			}
+		default:
+			term.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("aspas sem fechamento")
+	}
+	if term.Len() > 0 {
+		terms = append(terms, term.String())
+	}
+	return terms, nil
+}
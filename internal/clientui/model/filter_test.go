@@ -0,0 +1,47 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gustavo-silva98/adnotes/internal/clientui/model"
+)
+
+func TestParseListFilter(t *testing.T) {
+	f, err := model.ParseListFilter(`#projeto notebook:"Trabalho/Reuniões 2025" from:2025-01-01 to:31/01/2025`, time.UTC)
+	if err != nil {
+		t.Fatalf("Erro ao interpretar filtro: %v", err)
+	}
+	want := model.ListFilter{
+		Tag:      "projeto",
+		Notebook: "Trabalho/Reuniões 2025",
+		From:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:       time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if f != want {
+		t.Errorf("Filtro = %+v, esperado %+v", f, want)
+	}
+
+	again, err := model.ParseListFilter(f.String(), time.UTC)
+	if err != nil || again != f {
+		t.Errorf("String() não volta ao mesmo filtro: %q -> %+v (%v)", f.String(), again, err)
+	}
+
+	if f, err := model.ParseListFilter("   ", time.UTC); err != nil || f != (model.ListFilter{}) {
+		t.Errorf("Filtro vazio deveria limpar tudo: %+v (%v)", f, err)
+	}
+}
+
+func TestParseListFilterErrors(t *testing.T) {
+	for _, input := range []string{
+		"projeto",
+		"cor:azul",
+		"from:ontem",
+		`notebook:"sem fim`,
+		"from:2025-02-01 to:2025-01-01",
+	} {
+		if _, err := model.ParseListFilter(input, time.UTC); err == nil {
+			t.Errorf("%q deveria ser inválido", input)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/gustavo-silva98/adnotes/internal/clientui/keys"
+)
+
+// globalKeys são tratados em Update antes do estado, valendo em todas as telas.
+var globalKeys = []string{"Read"}
+
+// stateKeys lista, por estado, os bindings tratados pela tela: help aparece na
+// barra de ajuda (nessa ordem) e hidden só entra na checagem de conflitos.
+var stateKeys = map[SessionState]struct {
+	help   []string
+	hidden []string
+}{
+	InsertNoteState: {
+		help:   []string{"Save", "Read", "Title", "Reminder", "Quit", "FullSearch"},
+		hidden: []string{"Esc"},
+	},
+	ReadNotesState: {
+		help:   []string{"PageBack", "Enter", "FullSearch", "TagFilter", "Notebooks", "MoveNote", "Pin", "Sort", "SortDir", "Filter", "Preview", "History", "Trash", "Delete", "Quit"},
+		hidden: []string{"Up", "Down", "Back", "Undo"},
+	},
+	EditNoteSate: {
+		help:   []string{"Save", "Reminder", "Quit"},
+		hidden: []string{"Back"},
+	},
+	ConfirmEditSate:        {hidden: []string{"Yes", "No"}},
+	DeleteNoteState:        {hidden: []string{"Yes", "No"}},
+	ConfirmKillServerState: {hidden: []string{"Yes", "No"}},
+	InitServerState: {
+		help:   []string{"Quit"},
+		hidden: []string{"Back"},
+	},
+	FullSearchNoteState: {
+		help:   []string{"Quit", "Read", "TagFilter", "Fuzzy", "SaveSearch", "Preview"},
+		hidden: []string{"Up", "Down", "Enter", "Back"},
+	},
+	ReminderState: {
+		help:   []string{"Snooze", "Dismiss", "Enter"},
+		hidden: []string{"Quit", "Back"},
+	},
+	NotebookPickerState: {
+		help:   []string{"Enter", "Notebooks", "Delete", "Back"},
+		hidden: []string{"Up", "Down", "Quit"},
+	},
+	HistoryState: {
+		help:   []string{"Up", "Down", "Enter", "Back"},
+		hidden: []string{"Quit"},
+	},
+	TrashState: {
+		help:   []string{"Enter", "Delete", "Back"},
+		hidden: []string{"Up", "Down", "Quit", "Yes", "No"},
+	},
+	FilterBarState: {
+		help:   []string{"Enter", "Back"},
+		hidden: []string{"Quit"},
+	},
+}
+
+// stateDesc troca a descrição de um binding quando o sentido muda na tela.
+var stateDesc = map[SessionState]map[string]string{
+	InsertNoteState:     {"Quit": "Quit"},
+	EditNoteSate:        {"Save": "Save Note", "Quit": "Quit Editing"},
+	InitServerState:     {"Quit": "Close Window"},
+	FullSearchNoteState: {"Quit": "Close Window"},
+	ReminderState:       {"Enter": "Close"},
+	NotebookPickerState: {"Enter": "Select", "Notebooks": "New Notebook", "Delete": "Delete Search"},
+	HistoryState:        {"Enter": "Restore"},
+	TrashState:          {"Enter": "Restore", "Delete": "Delete Forever"},
+	FilterBarState:      {"Enter": "Apply Filter", "Back": "Cancel"},
+}
+
+// StateHelp gera a barra de ajuda do estado a partir dos bindings ativos.
+func StateHelp(state SessionState, km keys.KeyMap) []key.Binding {
+	named := km.Named()
+	var out []key.Binding
+	for _, name := range stateKeys[state].help {
+		b := *named[name]
+		if desc, ok := stateDesc[state][name]; ok {
+			b.SetHelp(b.Help().Key, desc)
+		}
+		out = append(out, b)
+	}
+	return out
+}
+
+// ValidateKeyMap detecta teclas associadas a mais de um binding dentro do
+// mesmo estado, o que deixaria uma das ações inacessível.
+func ValidateKeyMap(km keys.KeyMap) error {
+	named := km.Named()
+
+	states := make([]SessionState, 0, len(stateKeys))
+	for state := range stateKeys {
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i] < states[j] })
+
+	var conflicts []string
+	for _, state := range states {
+		scope := stateKeys[state]
+		owner := map[string]string{}
+		names := append(append(append([]string{}, globalKeys...), scope.help...), scope.hidden...)
+		for _, name := range names {
+			for _, k := range named[name].Keys() {
+				if other, ok := owner[k]; ok && other != name {
+					conflicts = append(conflicts, fmt.Sprintf("estado %d: %q usada por %v e %v", state, k, other, name))
+					continue
+				}
+				owner[k] = name
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("conflito de teclas: %v", strings.Join(conflicts, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/gustavo-silva98/adnotes/internal/clientui/keys"
+	"github.com/gustavo-silva98/adnotes/internal/clientui/model"
+)
+
+func TestDefaultKeyMapHasNoConflicts(t *testing.T) {
+	if err := model.ValidateKeyMap(keys.Default); err != nil {
+		t.Errorf("Keymap padrão com conflitos: %v", err)
+	}
+}
+
+func TestValidateKeyMapDetectsConflict(t *testing.T) {
+	km := keys.Default
+	// Na tela de inserção, ctrl+s já salva a nota.
+	km.FullSearch = key.NewBinding(key.WithKeys("ctrl+s"))
+	if err := model.ValidateKeyMap(km); err == nil {
+		t.Error("Esperado conflito entre Save e FullSearch")
+	}
+}
+
+func TestValidateKeyMapAllowsReuseAcrossStates(t *testing.T) {
+	km := keys.Default
+	// "s" só é usado no lembrete; Delete só na leitura.
+	km.Delete = key.NewBinding(key.WithKeys("s"))
+	if err := model.ValidateKeyMap(km); err != nil {
+		t.Errorf("Mesma tecla em estados diferentes não é conflito: %v", err)
+	}
+}
+
+func TestStateHelpFollowsBindings(t *testing.T) {
+	km := keys.Default
+	km.Save = key.NewBinding(key.WithKeys("ctrl+w"), key.WithHelp(keys.HelpKey([]string{"ctrl+w"}), "Save and Quit"))
+
+	help := model.StateHelp(model.InsertNoteState, km)
+	if len(help) == 0 || help[0].Help().Key != "Ctrl + w" {
+		t.Errorf("Ajuda não reflete o binding ativo: %v", help)
+	}
+
+	help = model.StateHelp(model.EditNoteSate, km)
+	if help[0].Help().Desc != "Save Note" {
+		t.Errorf("Descrição específica do estado não aplicada: %q", help[0].Help().Desc)
+	}
+}
@@ -2,15 +2,18 @@ package model
 
 import (
 	"context"
+	"fmt"
 	"os"
-	"path/filepath"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/gustavo-silva98/adnotes/internal/clientui/keys"
+	"github.com/gustavo-silva98/adnotes/internal/clientui/markdown"
+	"github.com/gustavo-silva98/adnotes/internal/config"
 	"github.com/gustavo-silva98/adnotes/internal/repository/file"
 )
 
@@ -28,6 +31,11 @@ const (
 	InitServerState
 	FullSearchNoteState
 	SaveNewNoteState
+	ReminderState
+	NotebookPickerState
+	HistoryState
+	TrashState
+	FilterBarState
 )
 
 type Model struct {
@@ -36,7 +44,6 @@ type Model struct {
 	Help                  help.Model
 	Keys                  keys.KeyMap
 	Quitting              bool
-	MapNotes              map[int]file.Note
 	IndexQuery            int
 	Context               context.Context
 	DB                    file.Writer
@@ -52,8 +59,46 @@ type Model struct {
 	TextAreaSearch        textarea.Model
 	FullSearchBool        bool
 	FullSearchQuery       string
+	SearchTerms           []string
+	SearchErr             string
+	FuzzySearch           bool
+	SearchSeq             int
+	SearchNameInput       textinput.Model
 	FullSearchTimerCancel chan struct{}
+	TagFilter             string
+	SortBy                file.SortField
+	SortDir               file.SortDirection
+	DateFrom              int64
+	DateTo                int64
+	FilterInput           textinput.Model
+	FilterErr             string
+	NoteDates             string
+	NotebookID            int
+	NotebookName          string
+	Notebooks             []file.Notebook
+	NotebookCursor        int
+	NotebookMove          bool
+	NotebookInput         textinput.Model
+	NotebookErr           string
+	SavedSearches         []file.SavedSearch
+	SavedSearch           file.SavedSearch
+	Markdown              *markdown.Renderer
+	Preview               string
+	RawPreview            bool
+	HistoryNote           file.Note
+	Revisions             []file.Revision
+	RevisionCursor        int
+	RevisionDiff          string
+	TrashNotes            []file.Note
+	TrashCursor           int
+	TrashConfirm          bool
+	LastDeletedID         int
 	LogPath               string
+	ReminderNote          file.Note
+	ReminderInput         textinput.Model
+	TitleInput            textinput.Model
+	ReminderErr           string
+	Hotkeys               []config.Binding
 }
 
 func NewTextAreaEdit() textarea.Model {
@@ -111,16 +156,97 @@ func NewTextAreaSearch() textarea.Model {
 	return t
 }
 
-func New() Model {
+func NewReminderInput() textinput.Model {
+	t := textinput.New()
+	t.Prompt = "🔔 "
+	t.Placeholder = "in 2h, tomorrow 9:00, every monday..."
+	t.Cursor.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#DF21FF"))
+	t.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#7e40fa"))
+	t.PlaceholderStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("238"))
+	t.CharLimit = 64
+
+	return t
+}
+
+func NewTitleInput() textinput.Model {
+	t := textinput.New()
+	t.Prompt = "📝 "
+	t.Placeholder = "Título (opcional; sem título, vale o primeiro cabeçalho ou linha)"
+	t.Cursor.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#DF21FF"))
+	t.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#7e40fa"))
+	t.PlaceholderStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("238"))
+	t.CharLimit = 120
+
+	return t
+}
+
+func NewNotebookInput() textinput.Model {
+	t := textinput.New()
+	t.Prompt = "📁 "
+	t.Placeholder = "Novo caderno (Pai/Filho cria um subcaderno)"
+	t.Cursor.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#DF21FF"))
+	t.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#7e40fa"))
+	t.PlaceholderStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("238"))
+	t.CharLimit = 64
+
+	return t
+}
+
+func NewSearchNameInput() textinput.Model {
+	t := textinput.New()
+	t.Prompt = "💾 "
+	t.Placeholder = "Nome da busca salva (Enter salva, Esc cancela)"
+	t.Cursor.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#DF21FF"))
+	t.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#7e40fa"))
+	t.PlaceholderStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("238"))
+	t.CharLimit = 64
+
+	return t
+}
+
+func NewFilterInput() textinput.Model {
+	t := textinput.New()
+	t.Prompt = "🔎 "
+	t.Placeholder = `#tag notebook:"Pai/Filho" from:2025-01-01 to:2025-01-31`
+	t.Cursor.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#DF21FF"))
+	t.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#7e40fa"))
+	t.PlaceholderStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("238"))
+	t.CharLimit = 128
+
+	return t
+}
+
+// New monta o modelo inicial do TUI sobre o banco em DataDir. Retorna erro se
+// o banco não puder ser aberto ou migrado; nesse caso o TUI não deve subir.
+func New() (Model, error) {
 	ti := textarea.New()
 	ti.Placeholder = "Digite sua nota..."
 	ti.Focus()
 	ti.ShowLineNumbers = true
 	ctx := context.Background()
-	os.Mkdir(filepath.Join("..", "data"), os.ModePerm)
-	dbPath := filepath.Join("..", "/data/banco.db")
+	os.Mkdir(file.DataDir, os.ModePerm)
+	dbPath := file.DataPath("banco.db")
+	logPath := file.DataPath("logs.txt")
 
-	sql, _ := file.InitDB(dbPath, ctx)
+	sql, err := file.InitDB(dbPath, ctx)
+	if err != nil {
+		return Model{}, fmt.Errorf("erro ao abrir o banco %v: %w", dbPath, err)
+	}
+
+	cfg, err := config.Load(config.Path())
+	if err != nil {
+		file.WriteLog(err.Error(), logPath)
+	}
+	hotkeys, _ := cfg.HotkeyBindings()
+
+	keyMap, err := keys.Load(file.DataPath("keymap.json"), keys.Default)
+	if err == nil {
+		err = ValidateKeyMap(keyMap)
+	}
+	if err != nil {
+		file.WriteLog(err.Error(), logPath)
+		keyMap = keys.Default
+	}
 
 	textEdit := NewTextAreaEdit()
 	textareaSearch := NewTextAreaSearch()
@@ -129,7 +255,7 @@ func New() Model {
 		State:           InsertNoteState,
 		Textarea:        ti,
 		Help:            help.New(),
-		Keys:            keys.Default,
+		Keys:            keyMap,
 		IndexQuery:      firstIndex,
 		Context:         ctx,
 		DB:              sql,
@@ -137,6 +263,13 @@ func New() Model {
 		TextareaEdit:    textEdit,
 		TextAreaSearch:  textareaSearch,
 		FullSearchQuery: "",
-		LogPath:         dbPath,
-	}
+		LogPath:         logPath,
+		ReminderInput:   NewReminderInput(),
+		TitleInput:      NewTitleInput(),
+		NotebookInput:   NewNotebookInput(),
+		FilterInput:     NewFilterInput(),
+		SearchNameInput: NewSearchNameInput(),
+		Markdown:        markdown.New(markdown.DefaultStyle, lipgloss.ColorProfile()),
+		Hotkeys:         hotkeys,
+	}, nil
 }
@@ -3,9 +3,6 @@ package update
 import (
 	"context"
 	"fmt"
-	"os/exec"
-	"runtime"
-	"sort"
 	"strings"
 	"time"
 
@@ -14,7 +11,14 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/gustavo-silva98/adnotes/internal/clientui/model"
+	"github.com/gustavo-silva98/adnotes/internal/diff"
+	"github.com/gustavo-silva98/adnotes/internal/instance"
+	"github.com/gustavo-silva98/adnotes/internal/ipc"
+	"github.com/gustavo-silva98/adnotes/internal/notetitle"
+	"github.com/gustavo-silva98/adnotes/internal/reminder"
 	"github.com/gustavo-silva98/adnotes/internal/repository/file"
+	"github.com/gustavo-silva98/adnotes/internal/scheduler"
+	"github.com/gustavo-silva98/adnotes/internal/transfer"
 	"github.com/muesli/reflow/wordwrap"
 )
 
@@ -27,6 +31,16 @@ const PageSize = 10
 type resultEditTimeoutMsg struct{}
 type resultKillTimeoutMsg struct{}
 type fullSearchDebounceMsg struct{}
+
+// searchResultsMsg traz o resultado de uma busca feita fora do Update. query e
+// seq identificam a busca; resultados de uma busca que já foi substituída são
+// descartados.
+type searchResultsMsg struct {
+	query   string
+	seq     int
+	results []file.SearchResult
+	err     error
+}
 type resultSaveNewNote struct{}
 type noteItem struct {
 	title, desc  string
@@ -34,6 +48,13 @@ type noteItem struct {
 	Id           int
 	Reminder     int
 	PlusReminder int
+	NotebookID   int
+	Tags         []string
+	CreatedAt    int64
+	UpdatedAt    int64
+	Pinned       bool
+	NoteTitle    string
+	Matches      []string
 }
 
 func (i noteItem) Title() string       { return i.title }
@@ -41,6 +62,68 @@ func (i noteItem) Description() string { return i.desc }
 func (i noteItem) FilterValue() string { return i.title }
 func (i noteItem) IdValue() int        { return i.Id }
 
+// pinGlyph marca no título as notas fixadas.
+const pinGlyph = "📌 "
+
+// titleWidth é a largura máxima, em colunas, do título na lista.
+const titleWidth = 40
+
+func newNoteItem(note file.Note, by file.SortField) noteItem {
+	title := notetitle.Of(note.Title, note.NoteText, titleWidth)
+	if note.Pinned {
+		title = pinGlyph + title
+	}
+	return noteItem{
+		title:        title,
+		desc:         noteDescription(note, by),
+		NoteText:     note.NoteText,
+		Id:           note.ID,
+		Reminder:     note.Reminder,
+		PlusReminder: note.PlusReminder,
+		NotebookID:   note.NotebookID,
+		Tags:         note.Tags,
+		CreatedAt:    note.CreatedAt,
+		UpdatedAt:    note.UpdatedAt,
+		Pinned:       note.Pinned,
+		NoteTitle:    note.Title,
+	}
+}
+
+// newSearchItem monta o item de um resultado da busca, com o trecho
+// encontrado, e os termos realçados, no lugar da data.
+func newSearchItem(m *model.Model, result file.SearchResult) noteItem {
+	item := newNoteItem(result.Note, m.SortBy)
+	item.Matches = result.Matches
+	if result.Snippet != "" {
+		snippet := strings.Join(strings.Fields(result.Snippet), " ")
+		item.desc = m.Markdown.HighlightMarked(snippet, file.SnippetStart, file.SnippetEnd) + noteBadges(result.Note)
+	}
+	return item
+}
+
+// noteDescription monta a linha secundária do item: a data usada na ordenação
+// e, se houver, tags e lembrete.
+func noteDescription(note file.Note, by file.SortField) string {
+	noteTimestamp := time.Unix(note.CreatedAt, 0)
+	if by == file.SortUpdated {
+		noteTimestamp = time.Unix(note.UpdatedAt, 0)
+	}
+	desc := fmt.Sprintf("%v/%d/%v %v:%02d", noteTimestamp.Day(), noteTimestamp.Month(), noteTimestamp.Year(), noteTimestamp.Hour(), noteTimestamp.Minute())
+	return desc + noteBadges(note)
+}
+
+// noteBadges devolve as tags e o lembrete da nota, para a linha secundária.
+func noteBadges(note file.Note) string {
+	var desc string
+	for _, tag := range note.Tags {
+		desc += " #" + tag
+	}
+	if note.Reminder > 0 {
+		desc += " 🔔 " + reminder.Describe(note.Reminder, note.PlusReminder)
+	}
+	return desc
+}
+
 func Update(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
@@ -85,6 +168,48 @@ func Update(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
 		return UpdateSearchNotes(msg, m)
 	case model.SaveNewNoteState:
 		return updateResultSaveNewNote(msg, m)
+	case model.ReminderState:
+		return updateReminderState(msg, m)
+	case model.NotebookPickerState:
+		return updateNotebookPickerState(msg, m)
+	case model.HistoryState:
+		return updateHistoryState(msg, m)
+	case model.TrashState:
+		return updateTrashState(msg, m)
+	case model.FilterBarState:
+		return updateFilterBarState(msg, m)
+	}
+	return *m, nil
+}
+
+func updateReminderState(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		note := m.ReminderNote
+		switch {
+		case key.Matches(msg, m.Keys.Snooze):
+			next := scheduler.Snooze(note, time.Now())
+			if _, err := m.DB.SetReminder(ctx, note.ID, int(next), note.PlusReminder); err != nil {
+				file.WriteLog(err.Error(), m.LogPath)
+				m.ResultMessage = fmt.Sprintf("Erro ao adiar lembrete: %v", err)
+			} else {
+				m.ResultMessage = fmt.Sprintf("Lembrete adiado para %v", time.Unix(next, 0).Format("02/01 15:04"))
+			}
+			m.State = model.SaveNewNoteState
+			return updateResultSaveNewNote(msg, m)
+		case key.Matches(msg, m.Keys.Dismiss):
+			if _, err := m.DB.SetReminder(ctx, note.ID, 0, 0); err != nil {
+				file.WriteLog(err.Error(), m.LogPath)
+				m.ResultMessage = fmt.Sprintf("Erro ao dispensar lembrete: %v", err)
+			} else {
+				m.ResultMessage = "Lembrete dispensado."
+			}
+			m.State = model.SaveNewNoteState
+			return updateResultSaveNewNote(msg, m)
+		case key.Matches(msg, m.Keys.Quit, m.Keys.Back, m.Keys.Enter):
+			m.Quitting = true
+			return *m, tea.Quit
+		}
 	}
 	return *m, nil
 }
@@ -99,12 +224,16 @@ func UpdateConfirmKillServerState(msg tea.Msg, m *model.Model) (model.Model, tea
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, m.Keys.Yes):
-			err := KillProcess("server")
-			if err != nil {
-				panic(fmt.Sprintf("erro ao finalizar Server %v", err))
-			}
 			m.State = model.FinishServerState
 			m.ResultMessage = "Server terminated"
+			if _, err := ipc.Call(ipc.SocketPath(), ipc.Request{Command: ipc.CmdShutdown}); err != nil {
+				// Sem resposta no socket: finaliza exatamente o processo dono do lock.
+				file.WriteLog(err.Error(), m.LogPath)
+				if err := instance.Terminate(instance.LockPath()); err != nil {
+					file.WriteLog(err.Error(), m.LogPath)
+					m.ResultMessage = fmt.Sprintf("Erro ao finalizar server: %v", err)
+				}
+			}
 			return updateResultKillServerState(msg, m)
 		case key.Matches(msg, m.Keys.No):
 			m.Quitting = true
@@ -134,7 +263,7 @@ func UpdateInitServerState(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch {
-		case key.Matches(msg, m.Keys.Quit):
+		case key.Matches(msg, m.Keys.Quit, m.Keys.Back):
 			return *m, tea.Quit
 		}
 	}
@@ -151,11 +280,19 @@ func updateInsertNoteState(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, m.Keys.Save):
+			reminderAt, plusReminder, ok := parseReminderInput(m, 0, 0)
+			if !ok {
+				m.Textarea.Blur()
+				cmds = append(cmds, m.ReminderInput.Focus())
+				return *m, tea.Batch(cmds...)
+			}
 			noteExample := file.Note{
-				Hour:         (time.Now().Unix() - int64(time.Now().Second())),
+				CreatedAt:    (time.Now().Unix() - int64(time.Now().Second())),
+				Title:        m.TitleInput.Value(),
 				NoteText:     m.Textarea.Value(),
-				Reminder:     0,
-				PlusReminder: 0,
+				Reminder:     reminderAt,
+				PlusReminder: plusReminder,
+				NotebookID:   m.NotebookID,
 			}
 
 			_, err := m.DB.InsertNote(&noteExample, ctx)
@@ -168,6 +305,28 @@ func updateInsertNoteState(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
 			if m.Textarea.Focused() {
 				m.Textarea.Blur()
 			}
+			m.ReminderInput.Blur()
+			m.TitleInput.Blur()
+		case key.Matches(msg, m.Keys.Reminder):
+			if m.ReminderInput.Focused() {
+				m.ReminderInput.Blur()
+				cmds = append(cmds, m.Textarea.Focus())
+			} else {
+				m.Textarea.Blur()
+				m.TitleInput.Blur()
+				cmds = append(cmds, m.ReminderInput.Focus())
+			}
+			return *m, tea.Batch(cmds...)
+		case key.Matches(msg, m.Keys.Title):
+			if m.TitleInput.Focused() {
+				m.TitleInput.Blur()
+				cmds = append(cmds, m.Textarea.Focus())
+			} else {
+				m.Textarea.Blur()
+				m.ReminderInput.Blur()
+				cmds = append(cmds, m.TitleInput.Focus())
+			}
+			return *m, tea.Batch(cmds...)
 		case key.Matches(msg, m.Keys.FullSearch):
 			m.State = model.FullSearchNoteState
 			m.TextAreaSearch.SetWidth(m.TermWidth/2 - 4)
@@ -178,11 +337,11 @@ func updateInsertNoteState(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
 			m.Quitting = true
 			return *m, tea.Quit
 		case key.Matches(msg, m.Keys.Read):
-			m.ItemList = queryMapNotes(m)
+			m.ItemList = queryNotes(m)
 			m.State = model.ReadNotesState
 			return *m, nil
 		default:
-			if !m.Textarea.Focused() {
+			if !m.Textarea.Focused() && !m.ReminderInput.Focused() && !m.TitleInput.Focused() {
 				cmd = m.Textarea.Focus()
 				cmds = append(cmds, cmd)
 			}
@@ -190,6 +349,10 @@ func updateInsertNoteState(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
 	}
 	m.Textarea, cmd = m.Textarea.Update(msg)
 	cmds = append(cmds, cmd)
+	m.ReminderInput, cmd = m.ReminderInput.Update(msg)
+	cmds = append(cmds, cmd)
+	m.TitleInput, cmd = m.TitleInput.Update(msg)
+	cmds = append(cmds, cmd)
 
 	return *m, tea.Batch(cmds...)
 
@@ -210,7 +373,7 @@ func updateReadNoteState(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
 					m.CurrentPage += 1
 
 					// Atualiza a lista de itens.
-					m.ItemList = queryMapNotes(m)
+					m.ItemList = queryNotes(m)
 					m.ListModel.SetItems(m.ItemList)
 					paginateDown = true
 				}
@@ -223,7 +386,7 @@ func updateReadNoteState(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
 				}
 
 				// Atualiza a lista de itens.
-				m.ItemList = queryMapNotes(m)
+				m.ItemList = queryNotes(m)
 				m.ListModel.SetItems(m.ItemList)
 				paginateUp = true
 			}
@@ -232,7 +395,7 @@ func updateReadNoteState(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
 
 	// Só recarregue a lista se ItemList estiver vazia (primeira vez) ou se mudar de página
 	if len(m.ItemList) == 0 {
-		m.ItemList = queryMapNotes(m)
+		m.ItemList = queryNotes(m)
 		d := list.NewDefaultDelegate()
 		c := lipgloss.Color("#FE02FF")
 		c1 := lipgloss.Color("#7e40fa")
@@ -248,9 +411,10 @@ func updateReadNoteState(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
 		m.ListModel = l
 	}
 	m.ListModel.SetSize(m.TermWidth/2, m.TermHeight-5)
-	m.TextareaEdit.SetHeight(m.TermHeight - 5)
+	// Reserva uma linha abaixo do painel para as datas da nota.
+	m.TextareaEdit.SetHeight(m.TermHeight - 6)
 	m.TextareaEdit.SetWidth(m.TermWidth - m.ListModel.Width() - 2)
-	m.ListModel.Title = fmt.Sprintf("Notas (%v/%v)", m.CurrentPage, totalPages)
+	m.ListModel.Title = listTitle(m, "Notas") + fmt.Sprintf(" (%v/%v)", m.CurrentPage, totalPages)
 
 	m.ListModel, cmd = m.ListModel.Update(msg)
 	cmds = append(cmds, cmd)
@@ -263,19 +427,63 @@ func updateReadNoteState(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
 			if m.TextareaEdit.Value() != wrapped {
 				m.TextareaEdit.SetValue(wrapped)
 			}
+			updatePreview(m, note.NoteText)
+			m.NoteDates = noteDates(note)
 		}
+	} else {
+		m.NoteDates = ""
 	}
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch {
-		case key.Matches(msg, m.Keys.Quit):
+		case key.Matches(msg, m.Keys.Quit, m.Keys.Back):
 			m.Quitting = true
 			return *m, tea.Quit
 		case key.Matches(msg, m.Keys.PageBack):
 			m.State = model.InsertNoteState
 		case key.Matches(msg, m.Keys.Delete):
 			m.State = model.DeleteNoteState
+		case key.Matches(msg, m.Keys.TagFilter):
+			m.TagFilter = nextTagFilter(m)
+			reloadFirstPage(m)
+		case key.Matches(msg, m.Keys.Sort):
+			m.SortBy = nextSortField(m.SortBy)
+			// Datas começam pelas mais recentes; texto, de A a Z.
+			m.SortDir = file.SortDesc
+			if m.SortBy == file.SortText {
+				m.SortDir = file.SortAsc
+			}
+			reloadFirstPage(m)
+		case key.Matches(msg, m.Keys.SortDir):
+			if m.SortDir == file.SortAsc {
+				m.SortDir = file.SortDesc
+			} else {
+				m.SortDir = file.SortAsc
+			}
+			reloadFirstPage(m)
+		case key.Matches(msg, m.Keys.Filter):
+			return openFilterBar(m)
+		case key.Matches(msg, m.Keys.Pin):
+			togglePin(m)
+		case key.Matches(msg, m.Keys.Preview):
+			m.RawPreview = !m.RawPreview
+		case key.Matches(msg, m.Keys.Notebooks):
+			openNotebookPicker(m, false)
+		case key.Matches(msg, m.Keys.MoveNote):
+			if m.ListModel.SelectedItem() != nil {
+				openNotebookPicker(m, true)
+			}
+		case key.Matches(msg, m.Keys.History):
+			if m.ListModel.SelectedItem() != nil {
+				return openHistory(msg, m)
+			}
+		case key.Matches(msg, m.Keys.Trash):
+			openTrash(m)
+		case key.Matches(msg, m.Keys.Undo):
+			if m.LastDeletedID != 0 {
+				return undoDelete(msg, m)
+			}
 		case key.Matches(msg, m.Keys.FullSearch):
 			m.State = model.FullSearchNoteState
 			m.TextAreaSearch.SetWidth(m.TermWidth/2 - 4)
@@ -286,6 +494,7 @@ func updateReadNoteState(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
 		case key.Matches(msg, m.Keys.Enter):
 			// Ao entrar no modo de edição, inicialize e foque o TextareaEdit
 			m.State = model.EditNoteSate
+			resetReminderInput(m)
 			if !m.TextareaEdit.Focused() {
 				cmd = m.TextareaEdit.Focus()
 				cmds = append(cmds, cmd)
@@ -306,22 +515,42 @@ func updateEditNoteFunc(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
 
 	var cmd tea.Cmd
 	m.ListModel.SetSize(m.TermWidth/2, m.TermHeight-5)
-	m.TextareaEdit.SetHeight(m.TermHeight - 5)
+	// Reserva uma linha abaixo do editor para o campo de lembrete.
+	m.TextareaEdit.SetHeight(m.TermHeight - 6)
 	m.TextareaEdit.SetWidth(m.TermWidth - m.ListModel.Width() - 2)
 
-	m.TextareaEdit, cmd = m.TextareaEdit.Update(msg)
-	cmds = append(cmds, cmd)
+	if keyMsg, ok := msg.(tea.KeyMsg); !ok || !key.Matches(keyMsg, m.Keys.Reminder) {
+		m.TextareaEdit, cmd = m.TextareaEdit.Update(msg)
+		cmds = append(cmds, cmd)
+		m.ReminderInput, cmd = m.ReminderInput.Update(msg)
+		cmds = append(cmds, cmd)
+	}
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch {
-		case key.Matches(msg, m.Keys.Quit):
+		case key.Matches(msg, m.Keys.Quit, m.Keys.Back):
 			m.State = model.ReadNotesState
 			if m.TextareaEdit.Focused() {
 				m.TextareaEdit.Blur()
 			}
+			m.ReminderInput.Blur()
+
+		case key.Matches(msg, m.Keys.Reminder):
+			if m.ReminderInput.Focused() {
+				m.ReminderInput.Blur()
+				cmds = append(cmds, m.TextareaEdit.Focus())
+			} else {
+				m.TextareaEdit.Blur()
+				cmds = append(cmds, m.ReminderInput.Focus())
+			}
 
 		case key.Matches(msg, m.Keys.Save):
+			if _, _, ok := parseReminderInput(m, 0, 0); !ok {
+				m.TextareaEdit.Blur()
+				cmds = append(cmds, m.ReminderInput.Focus())
+				break
+			}
 			m.State = model.ConfirmEditSate
 		}
 
@@ -347,8 +576,9 @@ func updateConfirmDeleteNote(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd)
 						m.State = model.ReadNotesState
 					}
 					if rowsUpdated == 1 {
-						m.ResultMessage = fmt.Sprintf("Nota %v deletada com sucesso.", note.title)
-						m.ItemList = queryMapNotes(m)
+						m.LastDeletedID = note.Id
+						m.ResultMessage = fmt.Sprintf("Nota %v movida para a lixeira. %v desfaz.", note.title, m.Keys.Undo.Help().Key)
+						m.ItemList = queryNotes(m)
 						m.ListModel.SetItems(m.ItemList)
 						m.State = model.ResultEditState
 						return updateResultEditState(msg, m)
@@ -376,12 +606,14 @@ func updateConfirmEditNote(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
 		case key.Matches(msg, m.Keys.Yes):
 			if selected := m.ListModel.SelectedItem(); selected != nil {
 				if note, ok := selected.(noteItem); ok {
+					reminderAt, plusReminder, _ := parseReminderInput(m, note.Reminder, note.PlusReminder)
 					noteInput := file.Note{
 						ID:           note.Id,
-						Hour:         time.Now().Unix(),
+						UpdatedAt:    time.Now().Unix(),
+						Title:        note.NoteTitle,
 						NoteText:     m.TextareaEdit.Value(),
-						Reminder:     note.Reminder,
-						PlusReminder: note.PlusReminder,
+						Reminder:     reminderAt,
+						PlusReminder: plusReminder,
 					}
 					rowsUpdated, err := m.DB.UpdateEditNoteRepository(ctx, noteInput)
 					if err != nil {
@@ -390,7 +622,7 @@ func updateConfirmEditNote(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
 					}
 					if rowsUpdated == 1 {
 						m.ResultMessage = fmt.Sprintf("Nota %v editada com sucesso.", note.title)
-						m.ItemList = queryMapNotes(m)
+						m.ItemList = queryNotes(m)
 						m.ListModel.SetItems(m.ItemList)
 						m.State = model.ResultEditState
 						return updateResultEditState(msg, m)
@@ -405,6 +637,35 @@ func updateConfirmEditNote(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
 	return *m, tea.Batch(cmds...)
 }
 
+// parseReminderInput interpreta o campo de lembrete. Campo vazio mantém os
+// valores atuais; em caso de erro, grava a mensagem em m.ReminderErr.
+func parseReminderInput(m *model.Model, current, currentPlus int) (int, int, bool) {
+	input := strings.TrimSpace(m.ReminderInput.Value())
+	if input == "" {
+		m.ReminderErr = ""
+		return current, currentPlus, true
+	}
+	schedule, err := reminder.Parse(input, time.Now())
+	if err != nil {
+		m.ReminderErr = err.Error()
+		return current, currentPlus, false
+	}
+	m.ReminderErr = ""
+	return schedule.Reminder(), schedule.PlusReminder(), true
+}
+
+// resetReminderInput limpa o campo de lembrete e mostra o lembrete atual da
+// nota selecionada como placeholder.
+func resetReminderInput(m *model.Model) {
+	m.ReminderInput.Reset()
+	m.ReminderInput.Blur()
+	m.ReminderErr = ""
+	m.ReminderInput.Placeholder = model.NewReminderInput().Placeholder
+	if note, ok := m.ListModel.SelectedItem().(noteItem); ok && note.Reminder > 0 {
+		m.ReminderInput.Placeholder = reminder.Describe(note.Reminder, note.PlusReminder) + " (off para remover)"
+	}
+}
+
 func updateResultEditState(_ tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
 	// retorna o cmd que vai enviar resultEditTimeoutMsg após 500ms
 	return *m, tea.Tick(800*time.Millisecond, func(t time.Time) tea.Msg {
@@ -413,68 +674,7 @@ func updateResultEditState(_ tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
 }
 
 func helpMaker(m *model.Model) []key.Binding {
-	// helper pra formatar tecla+descrição
-	b := func(keys, helpText string) key.Binding {
-		return key.NewBinding(
-			key.WithKeys(keys),
-			key.WithHelp(keys, helpText),
-		)
-	}
-
-	switch m.State {
-	case model.InsertNoteState:
-		return []key.Binding{
-			b("Ctrl + s", "Save and Quit"),
-			b("Ctrl + r", "Read Notes"),
-			b("Ctrl + q", "Quit"),
-			b("Ctrl + a", "Advanced Search"),
-		}
-	case model.ReadNotesState:
-		return []key.Binding{
-			b("Alt + ←", "Insert Note"),
-			b("Enter", "Edit Note"),
-			b("Ctrl + a", "Advanced Search"),
-			b("Ctrl + d", "Delete Note"),
-			b("Ctrl + q", "Quit"),
-		}
-	case model.EditNoteSate:
-		return []key.Binding{
-			b("Ctrl + s", "Save Note"),
-			b("Ctrl + q", "Quit Editing"),
-		}
-	case model.InitServerState:
-		return []key.Binding{
-			b("Ctrl + q", "Close Window"),
-		}
-	case model.FullSearchNoteState:
-		return []key.Binding{
-			b("Ctrl + q", "Close Window"),
-			b("Ctrl + r", "Read Notes"),
-		}
-	}
-	return []key.Binding{}
-}
-
-func titleFormatter(title string) string {
-	maxLineLenght := 40
-	splitStr := strings.Split(title, ",")[0]
-	splitStr = strings.Split(splitStr, "\n")[0]
-	if len(splitStr) > maxLineLenght {
-		return splitStr[0:maxLineLenght] + "..."
-	}
-	return splitStr
-}
-
-func KillProcess(processName string) error {
-	switch runtime.GOOS {
-	case "windows":
-		processName += ".exe"
-		return exec.Command("taskkill", "/IM", processName, "/F").Run()
-	case "linux":
-		return exec.Command("pkill", "-f", processName).Run()
-	default:
-		return fmt.Errorf("sistema operacional não suportado: %v", runtime.GOOS)
-	}
+	return model.StateHelp(m.State, m.Keys)
 }
 
 func UpdateSearchNotes(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
@@ -497,7 +697,7 @@ func UpdateSearchNotes(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
 		d.Styles.NormalDesc = d.Styles.NormalDesc.Foreground(lipgloss.Color("#f2c9faff")).Faint(true)
 		l := list.New(m.ItemList, d, m.TermWidth/2, m.TermHeight-5)
 		l.Styles.Title = l.Styles.Title.Background(lipgloss.Color("#9D2EB0")).Foreground(lipgloss.Color("#E0D9F6"))
-		l.Title = "Resultados da Busca"
+		l.Title = listTitle(m, "Resultados da Busca")
 		l.SetShowHelp(false)
 		m.ListModel = l
 		m.ListModel.SetSize(m.TermWidth/2, m.TermHeight-5)
@@ -510,6 +710,10 @@ func UpdateSearchNotes(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
 		m.TextareaEdit.SetWidth(m.TermWidth - m.ListModel.Width() - 4)
 	}
 
+	if m.SearchNameInput.Focused() {
+		return updateSaveSearch(msg, m)
+	}
+
 	if !m.FullSearchBool {
 		m.TextAreaSearch.Placeholder = "Digite sua busca aqui..."
 		m.TextAreaSearch.Focus()
@@ -519,17 +723,40 @@ func UpdateSearchNotes(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch {
-		case key.Matches(msg, m.Keys.Quit):
+		case key.Matches(msg, m.Keys.Quit, m.Keys.Back):
 			m.Quitting = true
 			return *m, tea.Quit
 		case key.Matches(msg, m.Keys.Read):
 			m.State = model.ReadNotesState
+		case key.Matches(msg, m.Keys.TagFilter):
+			m.TagFilter = nextTagFilter(m)
+			m.ListModel.Title = listTitle(m, "Resultados da Busca")
+			return *m, searchNotes(m)
+		case key.Matches(msg, m.Keys.SaveSearch):
+			if strings.TrimSpace(m.FullSearchQuery) == "" {
+				m.SearchErr = "digite uma busca para salvar"
+				return *m, nil
+			}
+			m.SearchErr = ""
+			m.SearchNameInput.Reset()
+			m.TextAreaSearch.Blur()
+			return *m, m.SearchNameInput.Focus()
+		case key.Matches(msg, m.Keys.Fuzzy):
+			// Também antes do campo de busca, onde alt+f avançaria uma palavra.
+			m.FuzzySearch = !m.FuzzySearch
+			m.ListModel.Title = listTitle(m, "Resultados da Busca")
+			return *m, searchNotes(m)
+		case key.Matches(msg, m.Keys.Preview):
+			// Tratado antes do campo de busca, onde ctrl+p moveria o cursor.
+			m.RawPreview = !m.RawPreview
+			return *m, nil
 		case key.Matches(msg, m.Keys.Up, m.Keys.Down):
 			if !m.TextAreaSearch.Focused() {
 				isNavigating = true
 			}
 		case key.Matches(msg, m.Keys.Enter):
 			m.State = model.EditNoteSate
+			resetReminderInput(m)
 			if !m.TextareaEdit.Focused() {
 				cmd = m.TextareaEdit.Focus()
 				cmds = append(cmds, cmd)
@@ -551,7 +778,19 @@ func UpdateSearchNotes(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
 	case fullSearchDebounceMsg:
 		m.FullSearchBool = true
 		m.FullSearchTimerCancel = nil
-		m.ItemList = FullSearchQueryMapNotes(m)
+		cmds = append(cmds, searchNotes(m))
+	case searchResultsMsg:
+		if msg.seq != m.SearchSeq || msg.query != m.FullSearchQuery {
+			break
+		}
+		if msg.err != nil {
+			file.WriteLog(msg.err.Error(), m.LogPath)
+		}
+		items := make([]list.Item, 0, len(msg.results))
+		for _, result := range msg.results {
+			items = append(items, newSearchItem(m, result))
+		}
+		m.ItemList = items
 		m.ListModel.SetItems(m.ItemList)
 	}
 	if !isNavigating {
@@ -575,11 +814,18 @@ func UpdateSearchNotes(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
 			if m.TextareaEdit.Value() != wrapped {
 				m.TextareaEdit.SetValue(wrapped)
 			}
+			updatePreview(m, note.NoteText)
+			terms := m.SearchTerms
+			if m.FuzzySearch {
+				terms = note.Matches
+			}
+			m.Preview = m.Markdown.Highlight(m.Preview, terms)
 		}
 	} else {
 		if m.TextareaEdit.Value() != "" {
 			m.TextareaEdit.SetValue("")
 		}
+		m.Preview = ""
 	}
 
 	m.TextareaEdit, cmd = m.TextareaEdit.Update(tea.KeyMsg{Type: tea.KeyNull})
@@ -606,37 +852,33 @@ func debouncerFullSearchNote(m *model.Model, d time.Duration) tea.Cmd {
 	}
 }
 
-func FullSearchQueryMapNotes(m *model.Model) []list.Item {
-	mapQuery, err := m.DB.FullSearchNote(m.Context, m.FullSearchQuery)
+// searchNotes dispara a busca digitada, exata ou aproximada conforme
+// m.FuzzySearch, em um tea.Cmd: a busca aproximada leva centenas de
+// milissegundos em bancos grandes e travaria a tela dentro do Update. Os
+// resultados chegam em searchResultsMsg, na ordem de relevância. Os termos
+// ficam em m.SearchTerms para o realce da prévia, e um erro de sintaxe em
+// m.SearchErr, enquanto a busca segue literal.
+func searchNotes(m *model.Model) tea.Cmd {
+	q, err := file.ParseSearchQuery(m.FullSearchQuery, time.Now())
+	m.SearchTerms = q.Terms
+	m.SearchErr = ""
 	if err != nil {
-		return []list.Item{}
+		m.SearchErr = err.Error() + " (buscando o texto literal)"
 	}
-	m.MapNotes = mapQuery
-
-	var ids []int
-	for id := range m.MapNotes {
-		ids = append(ids, id)
+	search := m.DB.FullSearchNote
+	if m.FuzzySearch {
+		search = m.DB.FuzzySearchNote
 	}
-	sort.Sort(sort.Reverse(sort.IntSlice(ids)))
-
-	items := make([]list.Item, 0, len(mapQuery))
-	for _, id := range ids {
-		note := m.MapNotes[id]
-		noteTimestamp := time.Unix(note.Hour, 0)
-		items = append(items, noteItem{
-			title:        titleFormatter(note.NoteText),
-			desc:         fmt.Sprintf("%v/%d/%v %v:%02d", noteTimestamp.Day(), noteTimestamp.Month(), noteTimestamp.Year(), noteTimestamp.Hour(), noteTimestamp.Minute()),
-			NoteText:     note.NoteText,
-			Id:           id,
-			Reminder:     0,
-			PlusReminder: 0,
-		})
+	m.SearchSeq++
+	query, seq, filter, ctx := m.FullSearchQuery, m.SearchSeq, noteFilter(m), m.Context
+	return func() tea.Msg {
+		results, err := search(ctx, query, filter)
+		return searchResultsMsg{query: query, seq: seq, results: results, err: err}
 	}
-	return items
 }
 
 func getPaginationInfo(m *model.Model) (totalPages int, hasNextPage bool, hasPrevPage bool) {
-	totalRows, _ := m.DB.GetTotalCount(m.Context)
+	totalRows, _ := m.DB.GetTotalCount(m.Context, noteFilter(m))
 	totalPages = (totalRows + PageSize - 1) / PageSize
 	if totalPages == 0 {
 		totalPages = 1
@@ -647,31 +889,669 @@ func getPaginationInfo(m *model.Model) (totalPages int, hasNextPage bool, hasPre
 	return totalPages, hasNextPage, hasPrevPage
 }
 
-func queryMapNotes(m *model.Model) []list.Item {
-	mapQuery, err := m.DB.QueryNote(PageSize, (m.CurrentPage-1)*PageSize, m.Context)
+// queryNotes carrega a página atual da leitura, já na ordem escolhida.
+func queryNotes(m *model.Model) []list.Item {
+	notes, err := m.DB.QueryNotes(m.Context, file.QueryOptions{
+		NoteFilter: noteFilter(m),
+		Sort:       m.SortBy,
+		Direction:  m.SortDir,
+		// Fixadas ficam no topo da primeira página em qualquer ordenação.
+		PinnedFirst: true,
+		Limit:       PageSize,
+		Offset:      (m.CurrentPage - 1) * PageSize,
+	})
 	if err != nil {
 		file.WriteLog(err.Error(), m.LogPath)
 	}
-	m.MapNotes = mapQuery
 
-	var ids []int
-	for id := range m.MapNotes {
-		ids = append(ids, id)
+	items := make([]list.Item, 0, len(notes))
+	for _, note := range notes {
+		items = append(items, newNoteItem(note, m.SortBy))
 	}
-	sort.Sort(sort.Reverse(sort.IntSlice(ids)))
+	return items
+}
 
-	items := make([]list.Item, 0, len(mapQuery))
-	for _, id := range ids {
-		note := m.MapNotes[id]
-		noteTimestamp := time.Unix(note.Hour, 0)
-		items = append(items, noteItem{
-			title:        titleFormatter(note.NoteText),
-			desc:         fmt.Sprintf("%v/%d/%v %v:%02d", noteTimestamp.Day(), noteTimestamp.Month(), noteTimestamp.Year(), noteTimestamp.Hour(), noteTimestamp.Minute()),
-			NoteText:     note.NoteText,
-			Id:           id,
-			Reminder:     0,
-			PlusReminder: 0,
-		})
+// nextTagFilter avança o filtro para a próxima tag em uso; depois da última,
+// volta a mostrar todas as notas.
+func nextTagFilter(m *model.Model) string {
+	tags, err := m.DB.ListTags(m.Context)
+	if err != nil {
+		file.WriteLog(err.Error(), m.LogPath)
+		return ""
 	}
-	return items
+	for i, tag := range tags {
+		if tag.Name == m.TagFilter {
+			if i+1 < len(tags) {
+				return tags[i+1].Name
+			}
+			return ""
+		}
+	}
+	if m.TagFilter == "" && len(tags) > 0 {
+		return tags[0].Name
+	}
+	return ""
+}
+
+// noteFilter retorna o filtro ativo na leitura e na busca: caderno, tag e período.
+func noteFilter(m *model.Model) file.NoteFilter {
+	return file.NoteFilter{Tag: m.TagFilter, NotebookID: m.NotebookID, From: m.DateFrom, To: m.DateTo, Search: m.SavedSearch.Query}
+}
+
+// nextSortField avança para o próximo critério de ordenação, voltando ao primeiro.
+func nextSortField(current file.SortField) file.SortField {
+	for i, field := range file.SortFields {
+		if field == current {
+			return file.SortFields[(i+1)%len(file.SortFields)]
+		}
+	}
+	return file.SortFields[0]
+}
+
+// togglePin fixa ou desafixa a nota selecionada e recarrega a página,
+// mantendo a seleção na mesma nota se ela continuar visível.
+func togglePin(m *model.Model) {
+	note, ok := m.ListModel.SelectedItem().(noteItem)
+	if !ok {
+		return
+	}
+	var err error
+	if note.Pinned {
+		_, err = m.DB.UnpinNote(ctx, note.Id)
+	} else {
+		_, err = m.DB.PinNote(ctx, note.Id)
+	}
+	if err != nil {
+		file.WriteLog(err.Error(), m.LogPath)
+		return
+	}
+
+	m.ItemList = queryNotes(m)
+	m.ListModel.SetItems(m.ItemList)
+	for i, item := range m.ItemList {
+		if item.(noteItem).Id == note.Id {
+			m.ListModel.Select(i)
+			return
+		}
+	}
+	m.ListModel.Select(0)
+}
+
+// reloadFirstPage recarrega a leitura a partir da primeira página, depois de
+// mudar o filtro ou a ordenação.
+func reloadFirstPage(m *model.Model) {
+	m.CurrentPage = 1
+	m.ItemList = queryNotes(m)
+	m.ListModel.SetItems(m.ItemList)
+	m.ListModel.Select(0)
+	totalPages, _, _ := getPaginationInfo(m)
+	m.ListModel.Title = listTitle(m, "Notas") + fmt.Sprintf(" (%v/%v)", m.CurrentPage, totalPages)
+}
+
+// noteDates monta a linha de datas do painel de detalhes.
+func noteDates(note noteItem) string {
+	const layout = "02/01/2006 15:04"
+	return fmt.Sprintf("Criada %v · Editada %v",
+		time.Unix(note.CreatedAt, 0).Format(layout),
+		time.Unix(note.UpdatedAt, 0).Format(layout))
+}
+
+// listTitle acrescenta ao título da lista o caderno e a tag filtrados.
+func listTitle(m *model.Model, title string) string {
+	if m.NotebookID != 0 {
+		title += " · " + m.NotebookName
+	}
+	if m.SavedSearch.ID != 0 {
+		title += " · 🔎 " + m.SavedSearch.Name
+	}
+	if m.TagFilter != "" {
+		title += " #" + m.TagFilter
+	}
+	if m.DateFrom != 0 || m.DateTo != 0 {
+		title += " · " + dateRangeLabel(m.DateFrom, m.DateTo)
+	}
+	if m.State == model.ReadNotesState {
+		arrow := " ↓"
+		if m.SortDir == file.SortAsc {
+			arrow = " ↑"
+		}
+		title += arrow + m.SortBy.String()
+	}
+	if m.State == model.FullSearchNoteState && m.FuzzySearch {
+		title += " · aproximada"
+	}
+	return title
+}
+
+// dateRangeLabel descreve o período filtrado; to é exclusivo.
+func dateRangeLabel(from, to int64) string {
+	const layout = "02/01/06"
+	switch {
+	case to == 0:
+		return "desde " + time.Unix(from, 0).Format(layout)
+	case from == 0:
+		return "até " + time.Unix(to, 0).AddDate(0, 0, -1).Format(layout)
+	}
+	return time.Unix(from, 0).Format(layout) + "–" + time.Unix(to, 0).AddDate(0, 0, -1).Format(layout)
+}
+
+// openFilterBar abre a barra de filtro preenchida com o filtro atual.
+func openFilterBar(m *model.Model) (model.Model, tea.Cmd) {
+	current := model.ListFilter{Tag: m.TagFilter}
+	if m.NotebookID != 0 {
+		notebooks, err := m.DB.ListNotebooks(ctx)
+		if err != nil {
+			file.WriteLog(err.Error(), m.LogPath)
+		}
+		current.Notebook = transfer.NotebookPaths(notebooks)[m.NotebookID]
+	}
+	if m.DateFrom != 0 {
+		current.From = time.Unix(m.DateFrom, 0)
+	}
+	if m.DateTo != 0 {
+		current.To = time.Unix(m.DateTo, 0)
+	}
+
+	m.FilterErr = ""
+	m.FilterInput.SetValue(current.String())
+	m.FilterInput.Width = m.TermWidth/2 - 6
+	m.FilterInput.CursorEnd()
+	m.State = model.FilterBarState
+	return *m, m.FilterInput.Focus()
+}
+
+// updateFilterBarState edita a barra de filtro. Enter aplica o filtro (vazio
+// limpa todos) e Esc volta à leitura sem mudar nada; erros ficam na barra.
+func updateFilterBarState(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(keyMsg, m.Keys.Quit):
+			m.Quitting = true
+			return *m, tea.Quit
+		case key.Matches(keyMsg, m.Keys.Back):
+			m.FilterInput.Blur()
+			m.State = model.ReadNotesState
+			return *m, nil
+		case key.Matches(keyMsg, m.Keys.Enter):
+			if err := applyFilter(m, m.FilterInput.Value()); err != nil {
+				m.FilterErr = err.Error()
+				return *m, nil
+			}
+			m.FilterInput.Blur()
+			m.State = model.ReadNotesState
+			reloadFirstPage(m)
+			return *m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.FilterInput, cmd = m.FilterInput.Update(msg)
+	return *m, cmd
+}
+
+// applyFilter valida o texto da barra e, se tudo estiver certo, troca o
+// filtro da leitura de uma vez.
+func applyFilter(m *model.Model, text string) error {
+	f, err := model.ParseListFilter(text, time.Local)
+	if err != nil {
+		return err
+	}
+	tag := ""
+	if f.Tag != "" {
+		if tag, err = file.NormalizeTag(f.Tag); err != nil {
+			return err
+		}
+	}
+	notebookID, notebookName := 0, ""
+	if f.Notebook != "" {
+		notebooks, err := m.DB.ListNotebooks(ctx)
+		if err != nil {
+			return err
+		}
+		id, ok := transfer.FindNotebook(notebooks, f.Notebook)
+		if !ok {
+			return fmt.Errorf("caderno %q não encontrado", f.Notebook)
+		}
+		notebookID, notebookName = id, transfer.NotebookPaths(notebooks)[id]
+	}
+
+	m.TagFilter = tag
+	m.NotebookID, m.NotebookName = notebookID, notebookName
+	m.DateFrom, m.DateTo = 0, 0
+	if !f.From.IsZero() {
+		m.DateFrom = f.From.Unix()
+	}
+	if !f.To.IsZero() {
+		m.DateTo = f.To.Unix()
+	}
+	m.FilterErr = ""
+	return nil
+}
+
+// openNotebookPicker abre o seletor de cadernos. Com move, o caderno escolhido
+// recebe a nota selecionada; sem move, passa a ser o caderno exibido.
+func openNotebookPicker(m *model.Model, move bool) {
+	m.NotebookMove = move
+	m.NotebookErr = ""
+	m.NotebookInput.Reset()
+	m.NotebookInput.Blur()
+	loadNotebooks(m)
+
+	current := m.NotebookID
+	if note, ok := m.ListModel.SelectedItem().(noteItem); ok && move {
+		current = note.NotebookID
+	}
+	m.NotebookCursor = 0
+	for i, nb := range m.Notebooks {
+		if nb.ID == current {
+			m.NotebookCursor = i
+		}
+	}
+	for i, search := range m.SavedSearches {
+		if search.ID == m.SavedSearch.ID {
+			m.NotebookCursor = len(m.Notebooks) + i
+		}
+	}
+	m.State = model.NotebookPickerState
+}
+
+// loadNotebooks recarrega os cadernos do seletor. Fora do modo de mover, a
+// primeira opção (ID 0) volta a mostrar as notas de todos os cadernos, e as
+// buscas salvas vêm depois dos cadernos, como cadernos virtuais.
+func loadNotebooks(m *model.Model) {
+	notebooks, err := m.DB.ListNotebooks(m.Context)
+	if err != nil {
+		file.WriteLog(err.Error(), m.LogPath)
+		m.NotebookErr = err.Error()
+	}
+	m.SavedSearches = nil
+	if !m.NotebookMove {
+		notebooks = append([]file.Notebook{{Name: "Todas as notas"}}, notebooks...)
+		m.SavedSearches, err = m.DB.ListSavedSearches(m.Context)
+		if err != nil {
+			file.WriteLog(err.Error(), m.LogPath)
+			m.NotebookErr = err.Error()
+		}
+	}
+	m.Notebooks = notebooks
+}
+
+func updateNotebookPickerState(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	keyMsg, isKey := msg.(tea.KeyMsg)
+	if m.NotebookInput.Focused() {
+		if isKey {
+			switch {
+			case key.Matches(keyMsg, m.Keys.Enter):
+				createNotebook(m)
+				return *m, nil
+			case key.Matches(keyMsg, m.Keys.Back):
+				m.NotebookInput.Reset()
+				m.NotebookInput.Blur()
+				m.NotebookErr = ""
+				return *m, nil
+			}
+		}
+		m.NotebookInput, cmd = m.NotebookInput.Update(msg)
+		return *m, cmd
+	}
+	if !isKey {
+		return *m, nil
+	}
+
+	switch {
+	case key.Matches(keyMsg, m.Keys.Quit):
+		m.Quitting = true
+		return *m, tea.Quit
+	case key.Matches(keyMsg, m.Keys.Back):
+		m.State = model.ReadNotesState
+	case key.Matches(keyMsg, m.Keys.Up):
+		if m.NotebookCursor > 0 {
+			m.NotebookCursor--
+		}
+	case key.Matches(keyMsg, m.Keys.Down):
+		if m.NotebookCursor < len(m.Notebooks)+len(m.SavedSearches)-1 {
+			m.NotebookCursor++
+		}
+	case key.Matches(keyMsg, m.Keys.Notebooks):
+		m.NotebookErr = ""
+		return *m, m.NotebookInput.Focus()
+	case key.Matches(keyMsg, m.Keys.Delete):
+		if search, ok := selectedSavedSearch(m); ok {
+			deleteSavedSearch(m, search)
+		}
+	case key.Matches(keyMsg, m.Keys.Enter):
+		if search, ok := selectedSavedSearch(m); ok {
+			m.SavedSearch = search
+			m.NotebookID, m.NotebookName = 0, ""
+			reloadFirstPage(m)
+			m.State = model.ReadNotesState
+			return *m, nil
+		}
+		if len(m.Notebooks) == 0 {
+			return *m, nil
+		}
+		notebook := m.Notebooks[m.NotebookCursor]
+		if m.NotebookMove {
+			return moveSelectedNote(msg, m, notebook)
+		}
+		m.NotebookID = notebook.ID
+		m.NotebookName = notebook.Name
+		m.SavedSearch = file.SavedSearch{}
+		m.CurrentPage = 1
+		m.ItemList = queryNotes(m)
+		m.ListModel.SetItems(m.ItemList)
+		m.ListModel.Select(0)
+		m.State = model.ReadNotesState
+	}
+	return *m, nil
+}
+
+// selectedSavedSearch devolve a busca salva sob o cursor do seletor, que vem
+// depois dos cadernos.
+func selectedSavedSearch(m *model.Model) (file.SavedSearch, bool) {
+	i := m.NotebookCursor - len(m.Notebooks)
+	if i < 0 || i >= len(m.SavedSearches) {
+		return file.SavedSearch{}, false
+	}
+	return m.SavedSearches[i], true
+}
+
+// deleteSavedSearch remove a busca salva do seletor. Se ela estava aberta, a
+// leitura volta a mostrar todas as notas.
+func deleteSavedSearch(m *model.Model, search file.SavedSearch) {
+	if _, err := m.DB.DeleteSavedSearch(ctx, search.ID); err != nil {
+		file.WriteLog(err.Error(), m.LogPath)
+		m.NotebookErr = err.Error()
+		return
+	}
+	if m.SavedSearch.ID == search.ID {
+		m.SavedSearch = file.SavedSearch{}
+		reloadFirstPage(m)
+	}
+	loadNotebooks(m)
+	m.NotebookCursor = min(m.NotebookCursor, len(m.Notebooks)+len(m.SavedSearches)-1)
+}
+
+// updateSaveSearch trata o campo de nome aberto por SaveSearch na busca.
+// Salva, a busca passa a ser exibida na leitura como caderno virtual.
+func updateSaveSearch(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(keyMsg, m.Keys.Enter):
+			name := strings.TrimSpace(m.SearchNameInput.Value())
+			id, err := m.DB.SaveSearch(ctx, name, m.FullSearchQuery)
+			if err != nil {
+				m.SearchErr = err.Error()
+				return *m, nil
+			}
+			m.SearchErr = ""
+			m.SearchNameInput.Reset()
+			m.SearchNameInput.Blur()
+			m.SavedSearch = file.SavedSearch{ID: int(id), Name: name, Query: strings.TrimSpace(m.FullSearchQuery)}
+			m.NotebookID, m.NotebookName = 0, ""
+			reloadFirstPage(m)
+			m.ResultMessage = fmt.Sprintf("Busca %q salva; ela fica nos cadernos (%v).", name, m.Keys.Notebooks.Help().Key)
+			m.State = model.ResultEditState
+			return updateResultEditState(msg, m)
+		case key.Matches(keyMsg, m.Keys.Back):
+			m.SearchNameInput.Reset()
+			m.SearchNameInput.Blur()
+			m.SearchErr = ""
+			return *m, m.TextAreaSearch.Focus()
+		}
+	}
+	m.SearchNameInput, cmd = m.SearchNameInput.Update(msg)
+	return *m, cmd
+}
+
+func moveSelectedNote(msg tea.Msg, m *model.Model, notebook file.Notebook) (model.Model, tea.Cmd) {
+	note, ok := m.ListModel.SelectedItem().(noteItem)
+	if !ok {
+		m.State = model.ReadNotesState
+		return *m, nil
+	}
+	if _, err := m.DB.MoveNote(ctx, note.Id, notebook.ID); err != nil {
+		file.WriteLog(err.Error(), m.LogPath)
+		m.ResultMessage = fmt.Sprintf("Erro ao mover nota: %v", err)
+	} else {
+		m.ResultMessage = fmt.Sprintf("Nota %v movida para %v.", note.title, notebook.Name)
+	}
+	m.ItemList = queryNotes(m)
+	m.ListModel.SetItems(m.ItemList)
+	m.State = model.ResultEditState
+	return updateResultEditState(msg, m)
+}
+
+// createNotebook cria o caderno digitado no seletor. "Pai/Filho" cria Filho
+// dentro de Pai, criando também Pai se ele ainda não existir.
+func createNotebook(m *model.Model) {
+	name := strings.TrimSpace(m.NotebookInput.Value())
+	parentID := 0
+	if parentName, child, nested := strings.Cut(name, "/"); nested {
+		name = child
+		parentName = strings.TrimSpace(parentName)
+		for _, nb := range m.Notebooks {
+			if nb.ID != 0 && nb.ParentID == 0 && strings.EqualFold(nb.Name, parentName) {
+				parentID = nb.ID
+			}
+		}
+		if parentID == 0 {
+			id, err := m.DB.CreateNotebook(ctx, parentName, 0)
+			if err != nil {
+				m.NotebookErr = err.Error()
+				return
+			}
+			parentID = int(id)
+		}
+	}
+
+	id, err := m.DB.CreateNotebook(ctx, name, parentID)
+	if err != nil {
+		m.NotebookErr = err.Error()
+		return
+	}
+	m.NotebookInput.Reset()
+	m.NotebookInput.Blur()
+	m.NotebookErr = ""
+	loadNotebooks(m)
+	for i, nb := range m.Notebooks {
+		if nb.ID == int(id) {
+			m.NotebookCursor = i
+		}
+	}
+}
+
+// updatePreview renderiza a nota selecionada como Markdown para o painel de
+// leitura. Em caso de erro, o painel mostra o texto original.
+func updatePreview(m *model.Model, text string) {
+	rendered, err := m.Markdown.Render(text, m.TextareaEdit.Width())
+	if err != nil {
+		file.WriteLog(err.Error(), m.LogPath)
+		rendered = text
+	}
+	m.Preview = rendered
+}
+
+// openHistory abre o histórico de versões da nota selecionada. Sem versões
+// anteriores, só avisa e continua na leitura.
+func openHistory(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
+	selected, ok := m.ListModel.SelectedItem().(noteItem)
+	if !ok {
+		return *m, nil
+	}
+	note, err := m.DB.GetNote(ctx, selected.Id)
+	var revisions []file.Revision
+	if err == nil {
+		revisions, err = m.DB.ListRevisions(ctx, selected.Id)
+	}
+	if err != nil {
+		file.WriteLog(err.Error(), m.LogPath)
+		m.ResultMessage = fmt.Sprintf("Erro ao carregar histórico: %v", err)
+		m.State = model.ResultEditState
+		return updateResultEditState(msg, m)
+	}
+	if len(revisions) == 0 {
+		m.ResultMessage = fmt.Sprintf("Nota %v não tem versões anteriores.", selected.title)
+		m.State = model.ResultEditState
+		return updateResultEditState(msg, m)
+	}
+
+	m.HistoryNote = note
+	m.Revisions = revisions
+	m.RevisionCursor = 0
+	updateRevisionDiff(m)
+	m.State = model.HistoryState
+	return *m, nil
+}
+
+// updateRevisionDiff compara a versão selecionada com o texto atual da nota.
+func updateRevisionDiff(m *model.Model) {
+	rev := m.Revisions[m.RevisionCursor]
+	from := "versão de " + time.Unix(rev.Hour, 0).Format("02/01/2006 15:04")
+	m.RevisionDiff = diff.Unified(rev.NoteText, m.HistoryNote.NoteText, from, "atual", 3)
+}
+
+func updateHistoryState(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return *m, nil
+	}
+
+	switch {
+	case key.Matches(keyMsg, m.Keys.Quit):
+		m.Quitting = true
+		return *m, tea.Quit
+	case key.Matches(keyMsg, m.Keys.Back):
+		m.State = model.ReadNotesState
+	case key.Matches(keyMsg, m.Keys.Up):
+		if m.RevisionCursor > 0 {
+			m.RevisionCursor--
+			updateRevisionDiff(m)
+		}
+	case key.Matches(keyMsg, m.Keys.Down):
+		if m.RevisionCursor < len(m.Revisions)-1 {
+			m.RevisionCursor++
+			updateRevisionDiff(m)
+		}
+	case key.Matches(keyMsg, m.Keys.Enter):
+		return restoreRevision(msg, m)
+	}
+	return *m, nil
+}
+
+// restoreRevision grava a versão selecionada como uma nova edição da nota.
+func restoreRevision(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
+	rev := m.Revisions[m.RevisionCursor]
+	rows, err := m.DB.RestoreRevision(ctx, rev.ID, time.Now().Unix())
+	switch {
+	case err != nil:
+		file.WriteLog(err.Error(), m.LogPath)
+		m.ResultMessage = fmt.Sprintf("Erro ao restaurar versão: %v", err)
+	case rows == 0:
+		m.ResultMessage = "A versão selecionada é igual ao texto atual."
+	default:
+		m.ResultMessage = fmt.Sprintf("Versão de %v restaurada.", time.Unix(rev.Hour, 0).Format("02/01/2006 15:04"))
+	}
+	m.ItemList = queryNotes(m)
+	m.ListModel.SetItems(m.ItemList)
+	m.State = model.ResultEditState
+	return updateResultEditState(msg, m)
+}
+
+// undoDelete tira da lixeira a última nota excluída na leitura.
+func undoDelete(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
+	if _, err := m.DB.RestoreNote(ctx, m.LastDeletedID); err != nil {
+		file.WriteLog(err.Error(), m.LogPath)
+		m.ResultMessage = fmt.Sprintf("Erro ao desfazer exclusão: %v", err)
+	} else {
+		m.ResultMessage = "Exclusão desfeita."
+	}
+	m.LastDeletedID = 0
+	m.ItemList = queryNotes(m)
+	m.ListModel.SetItems(m.ItemList)
+	m.State = model.ResultEditState
+	return updateResultEditState(msg, m)
+}
+
+// openTrash carrega as notas da lixeira e abre a tela da lixeira.
+func openTrash(m *model.Model) {
+	loadTrash(m)
+	m.TrashCursor = 0
+	m.TrashConfirm = false
+	m.State = model.TrashState
+}
+
+func loadTrash(m *model.Model) {
+	notes, err := m.DB.ListTrash(ctx)
+	if err != nil {
+		file.WriteLog(err.Error(), m.LogPath)
+	}
+	m.TrashNotes = notes
+	if m.TrashCursor >= len(notes) {
+		m.TrashCursor = max(len(notes)-1, 0)
+	}
+}
+
+func updateTrashState(msg tea.Msg, m *model.Model) (model.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return *m, nil
+	}
+
+	// Apagar definitivamente pede confirmação.
+	if m.TrashConfirm {
+		switch {
+		case key.Matches(keyMsg, m.Keys.Yes):
+			note := m.TrashNotes[m.TrashCursor]
+			if _, err := m.DB.PurgeNote(ctx, note.ID); err != nil {
+				file.WriteLog(err.Error(), m.LogPath)
+			}
+			if note.ID == m.LastDeletedID {
+				m.LastDeletedID = 0
+			}
+			m.TrashConfirm = false
+			loadTrash(m)
+		case key.Matches(keyMsg, m.Keys.No, m.Keys.Back):
+			m.TrashConfirm = false
+		}
+		return *m, nil
+	}
+
+	switch {
+	case key.Matches(keyMsg, m.Keys.Quit):
+		m.Quitting = true
+		return *m, tea.Quit
+	case key.Matches(keyMsg, m.Keys.Back):
+		m.State = model.ReadNotesState
+	case key.Matches(keyMsg, m.Keys.Up):
+		if m.TrashCursor > 0 {
+			m.TrashCursor--
+		}
+	case key.Matches(keyMsg, m.Keys.Down):
+		if m.TrashCursor < len(m.TrashNotes)-1 {
+			m.TrashCursor++
+		}
+	case key.Matches(keyMsg, m.Keys.Delete):
+		if len(m.TrashNotes) > 0 {
+			m.TrashConfirm = true
+		}
+	case key.Matches(keyMsg, m.Keys.Enter):
+		if len(m.TrashNotes) == 0 {
+			return *m, nil
+		}
+		note := m.TrashNotes[m.TrashCursor]
+		if _, err := m.DB.RestoreNote(ctx, note.ID); err != nil {
+			file.WriteLog(err.Error(), m.LogPath)
+		}
+		if note.ID == m.LastDeletedID {
+			m.LastDeletedID = 0
+		}
+		loadTrash(m)
+		m.ItemList = queryNotes(m)
+		m.ListModel.SetItems(m.ItemList)
+	}
+	return *m, nil
 }
@@ -3,9 +3,12 @@ package view
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/gustavo-silva98/adnotes/internal/clientui/model"
+	"github.com/gustavo-silva98/adnotes/internal/notetitle"
+	"github.com/muesli/reflow/wordwrap"
 )
 
 //var termWid, termHeight, _ = term.GetSize(os.Stdout.Fd())
@@ -66,6 +69,16 @@ func View(m model.Model) string {
 		output = FullSearchNoteView(m)
 	case model.SaveNewNoteState:
 		output = ResultEditModalOverlay(m, m.ResultMessage)
+	case model.ReminderState:
+		output = ReminderView(m)
+	case model.NotebookPickerState:
+		output = NotebookPickerView(m)
+	case model.HistoryState:
+		output = HistoryView(m)
+	case model.TrashState:
+		output = TrashView(m)
+	case model.FilterBarState:
+		output = EditNoteView(m)
 	}
 
 	return output
@@ -97,8 +110,10 @@ func InsertNoteView(m model.Model) string {
 		Width(elementWidth)
 
 	content := fmt.Sprintf(
-		"Digite sua anotação abaixo. \n\n%s",
+		"Digite sua anotação abaixo. \n\n%s\n%s\n%s",
+		m.TitleInput.View(),
 		m.Textarea.View(),
+		reminderInputView(m),
 	)
 
 	mainContent := lipgloss.JoinVertical(
@@ -118,6 +133,281 @@ func InsertNoteView(m model.Model) string {
 	return output
 }
 
+func ReminderView(m model.Model) string {
+	elementWidth := m.TermWidth - (m.TermWidth / 5)
+	textHeight := m.TermHeight / 2
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FE02FF")).
+		Bold(true).
+		Width(elementWidth).
+		Align(lipgloss.Center).
+		MarginBottom(1)
+
+	textStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7e40fa")).
+		Padding(1, 2).
+		Width(elementWidth).
+		Height(textHeight)
+
+	helpStyle := lipgloss.NewStyle().
+		AlignHorizontal(lipgloss.Center).
+		Width(elementWidth).
+		MarginTop(1)
+
+	title := "🔔 Lembrete"
+	if m.ReminderNote.PlusReminder > 0 {
+		title = fmt.Sprintf("🔔 Lembrete (repete a cada %v)", time.Duration(m.ReminderNote.PlusReminder)*time.Second)
+	}
+
+	mainContent := lipgloss.JoinVertical(
+		lipgloss.Top,
+		titleStyle.Render(title),
+		textStyle.Render(wordwrap.String(m.ReminderNote.NoteText, elementWidth-6)),
+		helpStyle.Render(m.Help.ShortHelpView(m.HelpKeys)),
+	)
+
+	return lipgloss.Place(
+		m.TermWidth,
+		m.TermHeight,
+		lipgloss.Center, lipgloss.Center,
+		mainContent,
+	)
+}
+
+// NotebookPickerView lista os cadernos, com os subcadernos recuados abaixo do pai.
+func NotebookPickerView(m model.Model) string {
+	elementWidth := m.TermWidth / 2
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FE02FF")).
+		Bold(true).
+		MarginBottom(1)
+
+	itemStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#f2c9faff")).
+		Faint(true)
+
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FE02FF")).
+		Bold(true)
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7e40fa")).
+		Padding(1, 2).
+		Width(elementWidth)
+
+	helpStyle := lipgloss.NewStyle().
+		AlignHorizontal(lipgloss.Center).
+		Width(elementWidth).
+		MarginTop(1)
+
+	title := "📁 Cadernos"
+	if m.NotebookMove {
+		title = "📁 Mover nota para"
+	}
+
+	lines := []string{titleStyle.Render(title)}
+	for i, nb := range m.Notebooks {
+		label := nb.Name
+		if nb.ParentID != 0 {
+			label = "  └ " + label
+		}
+		if i == m.NotebookCursor {
+			lines = append(lines, selectedStyle.Render("> "+label))
+		} else {
+			lines = append(lines, itemStyle.Render("  "+label))
+		}
+	}
+	if len(m.SavedSearches) > 0 {
+		lines = append(lines, "", titleStyle.MarginBottom(0).Render("🔎 Buscas salvas"))
+	}
+	for i, search := range m.SavedSearches {
+		label := search.Name + " · " + search.Query
+		if len(m.Notebooks)+i == m.NotebookCursor {
+			lines = append(lines, selectedStyle.Render("> "+label))
+		} else {
+			lines = append(lines, itemStyle.Render("  "+label))
+		}
+	}
+
+	input := m.NotebookInput.View()
+	if m.NotebookErr != "" {
+		errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5F87"))
+		input += "\n" + errStyle.Render(m.NotebookErr)
+	}
+	lines = append(lines, "", input)
+
+	mainContent := lipgloss.JoinVertical(
+		lipgloss.Top,
+		boxStyle.Render(strings.Join(lines, "\n")),
+		helpStyle.Render(m.Help.ShortHelpView(m.HelpKeys)),
+	)
+
+	return lipgloss.Place(
+		m.TermWidth,
+		m.TermHeight,
+		lipgloss.Center, lipgloss.Center,
+		mainContent,
+	)
+}
+
+// HistoryView lista as versões anteriores da nota e, ao lado, o diff entre a
+// versão selecionada e o texto atual.
+func HistoryView(m model.Model) string {
+	listWidth := m.TermWidth / 3
+	diffWidth := m.TermWidth - listWidth - 4
+	contentHeight := m.TermHeight - 5
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FE02FF")).
+		Bold(true).
+		MarginBottom(1)
+
+	itemStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#f2c9faff")).
+		Faint(true)
+
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FE02FF")).
+		Bold(true)
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7e40fa")).
+		Padding(0, 1).
+		Height(contentHeight)
+
+	helpStyle := lipgloss.NewStyle().
+		AlignHorizontal(lipgloss.Center).
+		Width(m.TermWidth).
+		MarginTop(1)
+
+	// Título e margem ocupam duas linhas da caixa.
+	visible := max(contentHeight-2, 1)
+	first := 0
+	if m.RevisionCursor >= visible {
+		first = m.RevisionCursor - visible + 1
+	}
+	lines := []string{titleStyle.Render("🕘 Versões")}
+	for i := first; i < len(m.Revisions) && i < first+visible; i++ {
+		rev := m.Revisions[i]
+		label := time.Unix(rev.Hour, 0).Format("02/01/2006 15:04") + "  " + notetitle.Derive(rev.NoteText)
+		label = notetitle.Truncate(label, listWidth-4)
+		if i == m.RevisionCursor {
+			lines = append(lines, selectedStyle.Render("> "+label))
+		} else {
+			lines = append(lines, itemStyle.Render("  "+label))
+		}
+	}
+
+	diffLines := strings.Split(strings.TrimSuffix(m.RevisionDiff, "\n"), "\n")
+	if len(diffLines) > contentHeight {
+		diffLines = diffLines[:contentHeight]
+	}
+	for i, line := range diffLines {
+		diffLines[i] = diffLineStyle(line).Render(notetitle.Truncate(line, diffWidth-4))
+	}
+
+	content := lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		boxStyle.Width(listWidth).Render(strings.Join(lines, "\n")),
+		boxStyle.Width(diffWidth).Render(strings.Join(diffLines, "\n")),
+	)
+	return lipgloss.JoinVertical(lipgloss.Top, content, helpStyle.Render(m.Help.ShortHelpView(m.HelpKeys)))
+}
+
+// TrashView lista as notas da lixeira, com a data de exclusão, e mostra ao
+// lado o texto da nota selecionada.
+func TrashView(m model.Model) string {
+	listWidth := m.TermWidth / 2
+	textWidth := m.TermWidth - listWidth - 4
+	contentHeight := m.TermHeight - 5
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FE02FF")).
+		Bold(true).
+		MarginBottom(1)
+
+	itemStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#f2c9faff")).
+		Faint(true)
+
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FE02FF")).
+		Bold(true)
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7e40fa")).
+		Padding(0, 1).
+		Height(contentHeight)
+
+	helpStyle := lipgloss.NewStyle().
+		AlignHorizontal(lipgloss.Center).
+		Width(m.TermWidth).
+		MarginTop(1)
+
+	lines := []string{titleStyle.Render(fmt.Sprintf("🚮 Lixeira (%d)", len(m.TrashNotes)))}
+	if len(m.TrashNotes) == 0 {
+		lines = append(lines, itemStyle.Render("A lixeira está vazia."))
+	}
+	// Título, margem e linha de confirmação ocupam três linhas da caixa.
+	visible := max(contentHeight-3, 1)
+	first := 0
+	if m.TrashCursor >= visible {
+		first = m.TrashCursor - visible + 1
+	}
+	for i := first; i < len(m.TrashNotes) && i < first+visible; i++ {
+		note := m.TrashNotes[i]
+		label := time.Unix(note.DeletedAt, 0).Format("02/01/2006 15:04") + "  " + notetitle.Of(note.Title, note.NoteText, listWidth)
+		label = notetitle.Truncate(label, listWidth-4)
+		if i == m.TrashCursor {
+			lines = append(lines, selectedStyle.Render("> "+label))
+		} else {
+			lines = append(lines, itemStyle.Render("  "+label))
+		}
+	}
+	if m.TrashConfirm {
+		errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5F87"))
+		question := fmt.Sprintf("Apagar definitivamente? (%v/%v)", m.Keys.Yes.Help().Key, m.Keys.No.Help().Key)
+		lines = append(lines, "", errStyle.Render(question))
+	}
+
+	var text string
+	if len(m.TrashNotes) > 0 {
+		text = wordwrap.String(m.TrashNotes[m.TrashCursor].NoteText, textWidth-2)
+		if textLines := strings.Split(text, "\n"); len(textLines) > contentHeight {
+			text = strings.Join(textLines[:contentHeight], "\n")
+		}
+	}
+
+	content := lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		boxStyle.Width(listWidth).Render(strings.Join(lines, "\n")),
+		boxStyle.Width(textWidth).Render(text),
+	)
+	return lipgloss.JoinVertical(lipgloss.Top, content, helpStyle.Render(m.Help.ShortHelpView(m.HelpKeys)))
+}
+
+// diffLineStyle colore as linhas do diff unificado pelo prefixo.
+func diffLineStyle(line string) lipgloss.Style {
+	style := lipgloss.NewStyle()
+	switch {
+	case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		return style.Bold(true).Foreground(lipgloss.Color("#9a6bf8ff"))
+	case strings.HasPrefix(line, "@@"):
+		return style.Foreground(lipgloss.Color("#7e40fa"))
+	case strings.HasPrefix(line, "+"):
+		return style.Foreground(lipgloss.Color("#84F5D5"))
+	case strings.HasPrefix(line, "-"):
+		return style.Foreground(lipgloss.Color("#FF5F87"))
+	}
+	return style.Faint(true)
+}
+
 func InitServerView(m model.Model) string {
 	logoHeight := (m.TermHeight / 10) * 6
 	textHeight := m.TermHeight - logoHeight
@@ -136,11 +426,9 @@ func InitServerView(m model.Model) string {
 		AlignHorizontal(lipgloss.Center).
 		Foreground(lipgloss.Color("#909090"))
 
-	options := []string{
-		"Ctrl + Shift + H -> Save Note",
-		"Ctrl + Shift + R -> Read Note",
-		"Ctrl + Shift + K -> Kill Server",
-		"Ctrl + Shift + D -> Advanced Search",
+	var options []string
+	for _, b := range m.Hotkeys {
+		options = append(options, fmt.Sprintf("%v -> %v", b.Combo.Display(), b.Action.Description))
 	}
 	options = KeysForInitState(options, 20)
 
@@ -172,6 +460,26 @@ func InitServerView(m model.Model) string {
 	return output
 }
 
+// reminderInputView mostra o campo de lembrete e, se houver, o erro de parse.
+func reminderInputView(m model.Model) string {
+	view := m.ReminderInput.View()
+	if m.ReminderErr != "" {
+		errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5F87"))
+		view += "  " + errStyle.Render(m.ReminderErr)
+	}
+	return view
+}
+
+// filterInputView mostra a barra de filtro abaixo da lista, com o erro, se houver.
+func filterInputView(m model.Model) string {
+	view := m.FilterInput.View()
+	if m.FilterErr != "" {
+		errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5F87"))
+		view += "\n" + errStyle.Render(m.FilterErr)
+	}
+	return view
+}
+
 func textareaEditView(m model.Model) string {
 	var textStyle = lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -180,6 +488,30 @@ func textareaEditView(m model.Model) string {
 	return textStyle.Render(m.TextareaEdit.View())
 }
 
+// showPreview indica se o painel da direita mostra a nota renderizada em vez
+// do texto cru, o que só acontece na leitura e na busca.
+func showPreview(m model.Model) bool {
+	if m.RawPreview || m.Preview == "" {
+		return false
+	}
+	return m.State == model.ReadNotesState || m.State == model.FullSearchNoteState
+}
+
+// previewView mostra a nota renderizada ocupando o mesmo espaço do editor,
+// cortando as linhas que não cabem.
+func previewView(m model.Model, border lipgloss.Style) string {
+	height := m.TextareaEdit.Height()
+	lines := strings.Split(m.Preview, "\n")
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+
+	return border.
+		Width(lipgloss.Width(m.TextareaEdit.View())).
+		Height(height).
+		Render(strings.Join(lines, "\n"))
+}
+
 func EditNoteView(m model.Model) string {
 	listWidth := m.TermWidth / 2
 	editorWidth := m.TermWidth - listWidth
@@ -201,8 +533,25 @@ func EditNoteView(m model.Model) string {
 		Width(m.TermWidth).
 		Height(helpHeight)
 
-	list := listStyle.Render(m.ListModel.View())
-	editor := editorStyle.Render(textareaEditView(m))
+	listContent := m.ListModel.View()
+	if m.State == model.FilterBarState {
+		listContent = lipgloss.JoinVertical(lipgloss.Left, listContent, filterInputView(m))
+	}
+	list := listStyle.Render(listContent)
+	editorContent := textareaEditView(m)
+	if showPreview(m) {
+		editorContent = previewView(m, lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("#7e40faff")))
+	}
+	if m.State == model.EditNoteSate || m.State == model.ConfirmEditSate {
+		editorContent = lipgloss.JoinVertical(lipgloss.Left, editorContent, reminderInputView(m))
+	}
+	if m.State == model.ReadNotesState && m.NoteDates != "" {
+		datesStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#f2c9faff")).Faint(true).PaddingLeft(1)
+		editorContent = lipgloss.JoinVertical(lipgloss.Left, editorContent, datesStyle.Render(m.NoteDates))
+	}
+	editor := editorStyle.Render(editorContent)
 	horizontal := lipgloss.JoinHorizontal(lipgloss.Top, list, editor)
 	output := lipgloss.JoinVertical(lipgloss.Top, horizontal, helpStyle.Render(m.Help.ShortHelpView(m.HelpKeys)))
 
@@ -401,8 +750,22 @@ func FullSearchNoteView(m model.Model) string {
 		Height(helpHeight)
 
 	searchBox := textStyle.Render(m.TextAreaSearch.View())
+	if m.SearchNameInput.Focused() || m.SearchErr != "" {
+		// O nome da busca a salvar, ou o erro, ocupa a linha da margem, sem
+		// empurrar a lista.
+		line := m.SearchNameInput.View()
+		if !m.SearchNameInput.Focused() {
+			line = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5F87")).Render(m.SearchErr)
+		} else if m.SearchErr != "" {
+			line += "  " + lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5F87")).Render(m.SearchErr)
+		}
+		searchBox = textStyle.MarginBottom(0).Render(m.TextAreaSearch.View()) + "\n" + lipgloss.NewStyle().MaxWidth(listWidth+2).Render(line)
+	}
 	list := listStyle.Render(m.ListModel.View())
 	editor := editorStyle.Render(m.TextareaEdit.View())
+	if showPreview(m) {
+		editor = previewView(m, editorStyle.Width(0).Height(0))
+	}
 	leftSide := lipgloss.JoinVertical(lipgloss.Top, searchBox, list)
 	horizontal := lipgloss.JoinHorizontal(lipgloss.Top, leftSide, editor)
 	output := lipgloss.JoinVertical(lipgloss.Top, horizontal, helpStyle.Render(m.Help.ShortHelpView(m.HelpKeys)))
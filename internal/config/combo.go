@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Modificadores canônicos, na ordem em que aparecem em Combo.String.
+var modifierOrder = []string{"ctrl", "shift", "alt", "super"}
+
+var modifierAliases = map[string]string{
+	"ctrl":    "ctrl",
+	"control": "ctrl",
+	"shift":   "shift",
+	"alt":     "alt",
+	"option":  "alt",
+	"super":   "super",
+	"win":     "super",
+	"cmd":     "super",
+	"meta":    "super",
+}
+
+// namedKeys são as teclas aceitas em uma hotkey global, além de a-z e 0-9.
+var namedKeys = map[string]string{
+	"space":  "space",
+	"enter":  "return",
+	"return": "return",
+	"esc":    "escape",
+	"escape": "escape",
+	"delete": "delete",
+	"tab":    "tab",
+	"left":   "left",
+	"right":  "right",
+	"up":     "up",
+	"down":   "down",
+}
+
+// Combo é uma combinação de hotkey global normalizada, como ctrl+shift+h.
+type Combo struct {
+	Modifiers []string
+	Key       string
+}
+
+// ParseCombo interpreta combinações como "ctrl+shift+h" ou "Alt+F5". Exige ao
+// menos um modificador, já que uma tecla solta global capturaria a digitação.
+func ParseCombo(s string) (Combo, error) {
+	parts := strings.Split(strings.ToLower(strings.ReplaceAll(s, " ", "")), "+")
+	if len(parts) < 2 {
+		return Combo{}, fmt.Errorf("combinação %q precisa de ao menos um modificador", s)
+	}
+
+	mods := map[string]bool{}
+	for _, p := range parts[:len(parts)-1] {
+		mod, ok := modifierAliases[p]
+		if !ok {
+			return Combo{}, fmt.Errorf("modificador desconhecido %q em %q", p, s)
+		}
+		if mods[mod] {
+			return Combo{}, fmt.Errorf("modificador %q repetido em %q", p, s)
+		}
+		mods[mod] = true
+	}
+
+	key, ok := normalizeKey(parts[len(parts)-1])
+	if !ok {
+		return Combo{}, fmt.Errorf("tecla desconhecida %q em %q", parts[len(parts)-1], s)
+	}
+
+	combo := Combo{Key: key}
+	for _, mod := range modifierOrder {
+		if mods[mod] {
+			combo.Modifiers = append(combo.Modifiers, mod)
+		}
+	}
+	return combo, nil
+}
+
+func normalizeKey(k string) (string, bool) {
+	if len(k) == 1 && (k[0] >= 'a' && k[0] <= 'z' || k[0] >= '0' && k[0] <= '9') {
+		return k, true
+	}
+	if name, ok := namedKeys[k]; ok {
+		return name, true
+	}
+	var n int
+	if _, err := fmt.Sscanf(k, "f%d", &n); err == nil && n >= 1 && n <= 20 && k == fmt.Sprintf("f%d", n) {
+		return k, true
+	}
+	return "", false
+}
+
+// String retorna a forma canônica, como "ctrl+shift+h".
+func (c Combo) String() string {
+	return strings.Join(append(append([]string{}, c.Modifiers...), c.Key), "+")
+}
+
+// Display retorna a combinação no formato exibido na interface, como "Ctrl + Shift + H".
+func (c Combo) Display() string {
+	parts := make([]string, 0, len(c.Modifiers)+1)
+	for _, m := range append(append([]string{}, c.Modifiers...), c.Key) {
+		parts = append(parts, strings.ToUpper(m[:1])+m[1:])
+	}
+	return strings.Join(parts, " + ")
+}
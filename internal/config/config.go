@@ -0,0 +1,191 @@
+// Package config lê o arquivo de configuração do PulseNote (config.json na
+// pasta de dados), que define, entre outras coisas, as hotkeys globais do server.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/gustavo-silva98/adnotes/internal/repository/file"
+)
+
+// Action é uma ação disparada por hotkey global. State é o estado aberto no client.
+type Action struct {
+	Name        string
+	State       string
+	Description string
+}
+
+// Actions lista as ações aceitas em "hotkeys", na ordem em que são exibidas.
+var Actions = []Action{
+	{Name: "insert-note", State: "InsertNote", Description: "Save Note"},
+	{Name: "read-notes", State: "ReadNote", Description: "Read Note"},
+	{Name: "kill-server", State: "ExecuteServer", Description: "Kill Server"},
+	{Name: "advanced-search", State: "AdvancedSearch", Description: "Advanced Search"},
+}
+
+// Config é o conteúdo do config.json. Hotkeys mapeia o nome da ação para a
+// combinação de teclas, como "ctrl+shift+h"; uma combinação vazia desativa a ação.
+// TrashRetentionDays é por quantos dias uma nota fica na lixeira antes de ser
+// apagada definitivamente; 0 mantém as notas na lixeira para sempre.
+type Config struct {
+	Hotkeys            map[string]string `json:"hotkeys"`
+	API                APIConfig         `json:"api"`
+	TrashRetentionDays int               `json:"trash_retention_days"`
+}
+
+// DefaultTrashRetentionDays é a retenção padrão da lixeira.
+const DefaultTrashRetentionDays = 30
+
+// APIConfig controla a API HTTP local do server. Ela fica desligada até
+// Enabled ser true e só escuta em endereços de loopback; toda requisição
+// precisa do cabeçalho "Authorization: Bearer <Token>".
+type APIConfig struct {
+	Enabled bool   `json:"enabled"`
+	Addr    string `json:"addr"`
+	Token   string `json:"token"`
+}
+
+// DefaultAPIAddr é o endereço padrão da API local.
+const DefaultAPIAddr = "127.0.0.1:7878"
+
+// Binding associa uma ação à sua combinação já validada.
+type Binding struct {
+	Action Action
+	Combo  Combo
+}
+
+// Default retorna a configuração usada quando não há arquivo.
+func Default() Config {
+	return Config{
+		Hotkeys: map[string]string{
+			"insert-note":     "ctrl+shift+h",
+			"read-notes":      "ctrl+shift+r",
+			"kill-server":     "ctrl+shift+k",
+			"advanced-search": "ctrl+shift+d",
+		},
+		API:                APIConfig{Addr: DefaultAPIAddr},
+		TrashRetentionDays: DefaultTrashRetentionDays,
+	}
+}
+
+// Path retorna o caminho padrão do arquivo de configuração.
+func Path() string {
+	return file.DataPath("config.json")
+}
+
+// Load lê e valida a configuração em path. Se o arquivo não existir, ele é
+// criado com os valores padrão para servir de modelo. Ações omitidas no
+// arquivo mantêm a hotkey padrão, assim como campos omitidos em "api".
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, Save(path, cfg)
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("erro ao ler configuração: %v", err)
+	}
+
+	user := Config{API: cfg.API, TrashRetentionDays: cfg.TrashRetentionDays}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&user); err != nil {
+		return Default(), fmt.Errorf("erro ao interpretar %v: %v", path, err)
+	}
+	for action, combo := range user.Hotkeys {
+		cfg.Hotkeys[action] = combo
+	}
+	cfg.API = user.API
+	cfg.TrashRetentionDays = user.TrashRetentionDays
+
+	if err := cfg.Validate(); err != nil {
+		return Default(), fmt.Errorf("configuração inválida em %v: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Save grava cfg em path, formatado para edição manual.
+func Save(path string, cfg Config) error {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Validate confere se todas as ações existem, se as combinações são válidas
+// e se nenhuma combinação está associada a mais de uma ação, além da seção api.
+func (c Config) Validate() error {
+	if _, err := c.HotkeyBindings(); err != nil {
+		return err
+	}
+	if c.TrashRetentionDays < 0 {
+		return fmt.Errorf("trash_retention_days não pode ser negativo: %d", c.TrashRetentionDays)
+	}
+	return c.API.Validate()
+}
+
+// TrashRetention retorna a retenção da lixeira, ou 0 se ela não expira.
+func (c Config) TrashRetention() time.Duration {
+	return time.Duration(c.TrashRetentionDays) * 24 * time.Hour
+}
+
+// Validate exige, com a API ligada, um token e um endereço de loopback.
+func (a APIConfig) Validate() error {
+	if !a.Enabled {
+		return nil
+	}
+	if a.Token == "" {
+		return errors.New("api: token obrigatório quando enabled é true")
+	}
+	host, _, err := net.SplitHostPort(a.Addr)
+	if err != nil {
+		return fmt.Errorf("api: endereço inválido %q: %v", a.Addr, err)
+	}
+	if host != "localhost" {
+		ip := net.ParseIP(host)
+		if ip == nil || !ip.IsLoopback() {
+			return fmt.Errorf("api: endereço %q não é de loopback", a.Addr)
+		}
+	}
+	return nil
+}
+
+// HotkeyBindings retorna as hotkeys ativas na ordem de Actions.
+func (c Config) HotkeyBindings() ([]Binding, error) {
+	known := map[string]bool{}
+	for _, a := range Actions {
+		known[a.Name] = true
+	}
+	for name := range c.Hotkeys {
+		if !known[name] {
+			return nil, fmt.Errorf("ação desconhecida em hotkeys: %q", name)
+		}
+	}
+
+	var bindings []Binding
+	used := map[string]string{}
+	for _, action := range Actions {
+		raw := c.Hotkeys[action.Name]
+		if raw == "" {
+			continue
+		}
+		combo, err := ParseCombo(raw)
+		if err != nil {
+			return nil, fmt.Errorf("hotkey de %q: %w", action.Name, err)
+		}
+		if other, ok := used[combo.String()]; ok {
+			return nil, fmt.Errorf("hotkey %q usada por %q e %q", combo, other, action.Name)
+		}
+		used[combo.String()] = action.Name
+		bindings = append(bindings, Binding{Action: action, Combo: combo})
+	}
+	return bindings, nil
+}
@@ -0,0 +1,148 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gustavo-silva98/adnotes/internal/config"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Erro ao escrever config de teste - %v", err)
+	}
+	return path
+}
+
+func TestParseCombo(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		display string
+	}{
+		{"ctrl+shift+h", "ctrl+shift+h", "Ctrl + Shift + H"},
+		{"Shift+Ctrl+H", "ctrl+shift+h", "Ctrl + Shift + H"},
+		{"alt + f5", "alt+f5", "Alt + F5"},
+		{"win+space", "super+space", "Super + Space"},
+		{"control+option+enter", "ctrl+alt+return", "Ctrl + Alt + Return"},
+		{"ctrl+1", "ctrl+1", "Ctrl + 1"},
+	}
+	for _, tt := range tests {
+		combo, err := config.ParseCombo(tt.input)
+		if err != nil {
+			t.Errorf("ParseCombo(%q) retornou erro: %v", tt.input, err)
+			continue
+		}
+		if combo.String() != tt.want {
+			t.Errorf("ParseCombo(%q) = %q, esperado %q", tt.input, combo, tt.want)
+		}
+		if combo.Display() != tt.display {
+			t.Errorf("Display de %q = %q, esperado %q", tt.input, combo.Display(), tt.display)
+		}
+	}
+}
+
+func TestParseComboInvalid(t *testing.T) {
+	for _, input := range []string{"h", "ctrl+", "ctrl+shift", "hyper+h", "ctrl+ctrl+h", "ctrl+f25", "ctrl+pageup"} {
+		if _, err := config.ParseCombo(input); err == nil {
+			t.Errorf("ParseCombo(%q) deveria falhar", input)
+		}
+	}
+}
+
+func TestLoadCreatesDefaultFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Erro ao carregar config inexistente - %v", err)
+	}
+	if cfg.Hotkeys["insert-note"] != "ctrl+shift+h" {
+		t.Errorf("Hotkey padrão não carregada: %v", cfg.Hotkeys)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Arquivo de configuração padrão não foi criado - %v", err)
+	}
+}
+
+func TestLoadOverridesSingleAction(t *testing.T) {
+	path := writeConfig(t, `{"hotkeys": {"read-notes": "alt+shift+n"}}`)
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Erro ao carregar config - %v", err)
+	}
+	bindings, err := cfg.HotkeyBindings()
+	if err != nil {
+		t.Fatalf("Erro ao montar bindings - %v", err)
+	}
+	if len(bindings) != len(config.Actions) {
+		t.Fatalf("Esperados %d bindings, obtidos %d", len(config.Actions), len(bindings))
+	}
+	for _, b := range bindings {
+		if b.Action.Name == "read-notes" && b.Combo.String() != "shift+alt+n" {
+			t.Errorf("Hotkey sobrescrita não aplicada: %v", b.Combo)
+		}
+		if b.Action.Name == "insert-note" && b.Combo.String() != "ctrl+shift+h" {
+			t.Errorf("Hotkey padrão perdida: %v", b.Combo)
+		}
+	}
+}
+
+func TestLoadDisablesAction(t *testing.T) {
+	path := writeConfig(t, `{"hotkeys": {"kill-server": ""}}`)
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Erro ao carregar config - %v", err)
+	}
+	bindings, _ := cfg.HotkeyBindings()
+	for _, b := range bindings {
+		if b.Action.Name == "kill-server" {
+			t.Error("Ação com hotkey vazia deveria ser desativada")
+		}
+	}
+}
+
+func TestLoadRejectsInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name, content, errPart string
+	}{
+		{"ação desconhecida", `{"hotkeys": {"open-browser": "ctrl+shift+b"}}`, "ação desconhecida"},
+		{"hotkey duplicada", `{"hotkeys": {"read-notes": "ctrl+shift+h"}}`, "usada por"},
+		{"tecla desconhecida", `{"hotkeys": {"read-notes": "ctrl+shift+pageup"}}`, "tecla desconhecida"},
+		{"campo desconhecido", `{"hotkey": {}}`, "unknown field"},
+		{"json inválido", `{"hotkeys": `, "erro ao interpretar"},
+		{"retenção negativa", `{"trash_retention_days": -1}`, "negativo"},
+		{"api sem token", `{"api": {"enabled": true}}`, "token obrigatório"},
+		{"api fora do loopback", `{"api": {"enabled": true, "token": "t", "addr": "0.0.0.0:7878"}}`, "loopback"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := config.Load(writeConfig(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.errPart) {
+				t.Errorf("Esperado erro contendo %q, obtido %v", tt.errPart, err)
+			}
+			if cfg.Hotkeys["insert-note"] != "ctrl+shift+h" {
+				t.Error("Config inválida deveria retornar os valores padrão")
+			}
+		})
+	}
+}
+
+func TestLoadAPIKeepsDefaultAddr(t *testing.T) {
+	cfg, err := config.Load(writeConfig(t, `{"api": {"enabled": true, "token": "segredo"}}`))
+	if err != nil {
+		t.Fatalf("Erro ao carregar config - %v", err)
+	}
+	if !cfg.API.Enabled || cfg.API.Token != "segredo" || cfg.API.Addr != config.DefaultAPIAddr {
+		t.Errorf("Seção api inesperada: %+v", cfg.API)
+	}
+	if cfg.TrashRetentionDays != config.DefaultTrashRetentionDays {
+		t.Errorf("Retenção da lixeira omitida deveria manter o padrão, obtida %d", cfg.TrashRetentionDays)
+	}
+}
@@ -0,0 +1,196 @@
+// Package diff compara textos linha a linha e formata o resultado como um
+// diff unificado, usado no histórico de versões das notas.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Op é o tipo de uma linha no resultado da comparação.
+type Op int
+
+const (
+	Equal Op = iota
+	Delete
+	Insert
+)
+
+// Line é uma linha do resultado: presente nos dois textos, só no antigo
+// (Delete) ou só no novo (Insert).
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// maxCells limita a tabela da LCS. Acima disso, o trecho que difere é tratado
+// como substituído por inteiro, o que ainda gera um diff válido.
+const maxCells = 4_000_000
+
+// Lines compara a e b linha a linha pela maior subsequência comum.
+func Lines(a, b string) []Line {
+	x, y := splitLines(a), splitLines(b)
+
+	// Prefixo e sufixo comuns ficam fora da tabela.
+	prefix := 0
+	for prefix < len(x) && prefix < len(y) && x[prefix] == y[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(x)-prefix && suffix < len(y)-prefix && x[len(x)-1-suffix] == y[len(y)-1-suffix] {
+		suffix++
+	}
+
+	var out []Line
+	for _, l := range x[:prefix] {
+		out = append(out, Line{Equal, l})
+	}
+	out = append(out, lcs(x[prefix:len(x)-suffix], y[prefix:len(y)-suffix])...)
+	for _, l := range x[len(x)-suffix:] {
+		out = append(out, Line{Equal, l})
+	}
+	return out
+}
+
+func lcs(x, y []string) []Line {
+	var out []Line
+	if len(x)*len(y) > maxCells {
+		for _, l := range x {
+			out = append(out, Line{Delete, l})
+		}
+		for _, l := range y {
+			out = append(out, Line{Insert, l})
+		}
+		return out
+	}
+
+	// table[i][j] é o tamanho da LCS de x[i:] e y[j:].
+	table := make([][]int, len(x)+1)
+	for i := range table {
+		table[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				table[i][j] = table[i+1][j+1] + 1
+			} else {
+				table[i][j] = max(table[i+1][j], table[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			out = append(out, Line{Equal, x[i]})
+			i++
+			j++
+		case table[i+1][j] >= table[i][j+1]:
+			out = append(out, Line{Delete, x[i]})
+			i++
+		default:
+			out = append(out, Line{Insert, y[j]})
+			j++
+		}
+	}
+	for ; i < len(x); i++ {
+		out = append(out, Line{Delete, x[i]})
+	}
+	for ; j < len(y); j++ {
+		out = append(out, Line{Insert, y[j]})
+	}
+	return out
+}
+
+// splitLines separa o texto em linhas, ignorando a quebra final.
+func splitLines(s string) []string {
+	s = strings.TrimSuffix(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// Unified formata a diferença entre a e b como diff unificado, com context
+// linhas de contexto em volta de cada trecho alterado. Retorna "" se os textos
+// forem iguais.
+func Unified(a, b, fromName, toName string, context int) string {
+	lines := Lines(a, b)
+
+	var changed []int
+	for i, l := range lines {
+		if l.Op != Equal {
+			changed = append(changed, i)
+		}
+	}
+	if len(changed) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %v\n+++ %v\n", fromName, toName)
+
+	for k := 0; k < len(changed); {
+		// Junta as alterações cujo contexto se sobrepõe em um único trecho.
+		last := k
+		for last+1 < len(changed) && changed[last+1]-changed[last] <= 2*context+1 {
+			last++
+		}
+		start := max(changed[k]-context, 0)
+		end := min(changed[last]+context+1, len(lines))
+		writeHunk(&sb, lines, start, end)
+		k = last + 1
+	}
+	return sb.String()
+}
+
+func writeHunk(sb *strings.Builder, lines []Line, start, end int) {
+	// Posição do trecho em cada texto: linhas anteriores a start em a e em b.
+	aLine, bLine := 1, 1
+	for _, l := range lines[:start] {
+		if l.Op != Insert {
+			aLine++
+		}
+		if l.Op != Delete {
+			bLine++
+		}
+	}
+	aCount, bCount := 0, 0
+	for _, l := range lines[start:end] {
+		if l.Op != Insert {
+			aCount++
+		}
+		if l.Op != Delete {
+			bCount++
+		}
+	}
+	// Trechos vazios apontam para a linha anterior, como no diff do GNU.
+	if aCount == 0 {
+		aLine--
+	}
+	if bCount == 0 {
+		bLine--
+	}
+
+	fmt.Fprintf(sb, "@@ -%v +%v @@\n", hunkRange(aLine, aCount), hunkRange(bLine, bCount))
+	for _, l := range lines[start:end] {
+		switch l.Op {
+		case Equal:
+			sb.WriteString(" ")
+		case Delete:
+			sb.WriteString("-")
+		case Insert:
+			sb.WriteString("+")
+		}
+		sb.WriteString(l.Text)
+		sb.WriteString("\n")
+	}
+}
+
+func hunkRange(line, count int) string {
+	if count == 1 {
+		return fmt.Sprint(line)
+	}
+	return fmt.Sprintf("%d,%d", line, count)
+}
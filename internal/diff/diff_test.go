@@ -0,0 +1,65 @@
+package diff_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gustavo-silva98/adnotes/internal/diff"
+)
+
+func TestLines(t *testing.T) {
+	got := diff.Lines("a\nb\nc\n", "a\nx\nc\nd")
+	want := []diff.Line{
+		{Op: diff.Equal, Text: "a"},
+		{Op: diff.Delete, Text: "b"},
+		{Op: diff.Insert, Text: "x"},
+		{Op: diff.Equal, Text: "c"},
+		{Op: diff.Insert, Text: "d"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Lines = %+v, esperado %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Linha %d = %+v, esperado %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestUnified(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10"
+	b := "1\n2\ndois\n4\n5\n6\n7\n8\n9\n10\n11"
+
+	want := strings.Join([]string{
+		"--- antes",
+		"+++ depois",
+		"@@ -2,3 +2,3 @@",
+		" 2",
+		"-3",
+		"+dois",
+		" 4",
+		"@@ -10 +10,2 @@",
+		" 10",
+		"+11",
+		"",
+	}, "\n")
+	if got := diff.Unified(a, b, "antes", "depois", 1); got != want {
+		t.Errorf("Unified =\n%v\nesperado\n%v", got, want)
+	}
+}
+
+func TestUnifiedMergesCloseHunksAndEmptySides(t *testing.T) {
+	got := diff.Unified("a\nb\nc", "A\nb\nC", "x", "y", 1)
+	if strings.Count(got, "@@ ") != 1 || !strings.Contains(got, "@@ -1,3 +1,3 @@") {
+		t.Errorf("Alterações próximas deveriam formar um único trecho:\n%v", got)
+	}
+
+	got = diff.Unified("", "nova", "x", "y", 3)
+	if !strings.Contains(got, "@@ -0,0 +1 @@\n+nova\n") {
+		t.Errorf("Diff a partir de texto vazio inesperado:\n%v", got)
+	}
+
+	if got := diff.Unified("igual\n", "igual", "x", "y", 3); got != "" {
+		t.Errorf("Textos iguais deveriam gerar diff vazio, obtido %q", got)
+	}
+}
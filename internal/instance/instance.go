@@ -0,0 +1,118 @@
+// Package instance garante que só exista um server em execução, através de um
+// arquivo de lock exclusivo na pasta de dados que também guarda o PID do dono.
+package instance
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gustavo-silva98/adnotes/internal/repository/file"
+)
+
+// ErrAlreadyRunning indica que outro processo já detém o lock.
+var ErrAlreadyRunning = errors.New("server já está em execução")
+
+// ErrNotRunning indica que nenhum processo detém o lock.
+var ErrNotRunning = errors.New("server não está em execução")
+
+// Lock é o lock exclusivo obtido por Acquire. O lock do sistema operacional é
+// liberado automaticamente se o processo morrer, então um arquivo que sobrou
+// de uma execução anterior não impede uma nova.
+type Lock struct {
+	file *os.File
+}
+
+// LockPath retorna o caminho padrão do arquivo de lock do server.
+func LockPath() string {
+	return file.DataPath("server.lock")
+}
+
+// Acquire obtém o lock em path e grava o PID do processo atual. Se outro
+// processo já o detém, retorna ErrAlreadyRunning.
+func Acquire(path string) (*Lock, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir arquivo de lock: %v", err)
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		if errors.Is(err, errLocked) {
+			return nil, ErrAlreadyRunning
+		}
+		return nil, fmt.Errorf("erro ao obter lock: %v", err)
+	}
+
+	if err := f.Truncate(0); err != nil {
+		unlockFile(f)
+		f.Close()
+		return nil, fmt.Errorf("erro ao gravar PID: %v", err)
+	}
+	if _, err := f.WriteAt([]byte(strconv.Itoa(os.Getpid())), 0); err != nil {
+		unlockFile(f)
+		f.Close()
+		return nil, fmt.Errorf("erro ao gravar PID: %v", err)
+	}
+	return &Lock{file: f}, nil
+}
+
+// Release apaga o PID e libera o lock. O arquivo fica no lugar: removê-lo
+// abriria uma brecha em que outro processo obtém o lock em um arquivo que em
+// seguida some, e um terceiro cria um novo e também o obtém.
+func (l *Lock) Release() error {
+	var errs []error
+	if err := l.file.Truncate(0); err != nil {
+		errs = append(errs, fmt.Errorf("erro ao apagar PID: %v", err))
+	}
+	if err := unlockFile(l.file); err != nil {
+		errs = append(errs, fmt.Errorf("erro ao liberar lock: %v", err))
+	}
+	if err := l.file.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("erro ao fechar arquivo de lock: %v", err))
+	}
+	return errors.Join(errs...)
+}
+
+// ReadPID lê o PID gravado no arquivo de lock.
+func ReadPID(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("PID inválido no arquivo de lock: %q", data)
+	}
+	return pid, nil
+}
+
+// Terminate encerra o processo que detém o lock em path. O PID só é usado se
+// o lock ainda estiver ativo, o que garante que ele pertence a um server vivo
+// e não a um processo qualquer que reaproveitou o número.
+func Terminate(path string) error {
+	l, err := Acquire(path)
+	if err == nil {
+		if err := l.Release(); err != nil {
+			return err
+		}
+		return ErrNotRunning
+	}
+	if !errors.Is(err, ErrAlreadyRunning) {
+		return err
+	}
+
+	pid, err := ReadPID(path)
+	if err != nil {
+		return err
+	}
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return fmt.Errorf("processo %d não encontrado: %v", pid, err)
+	}
+	if err := proc.Kill(); err != nil {
+		return fmt.Errorf("erro ao finalizar processo %d: %v", pid, err)
+	}
+	return nil
+}
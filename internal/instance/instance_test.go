@@ -0,0 +1,145 @@
+package instance_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gustavo-silva98/adnotes/internal/instance"
+)
+
+// Quando PULSENOTE_LOCK_HELPER está definido, o binário de teste age como um
+// server falso: obtém o lock no caminho informado e espera ser finalizado.
+func TestMain(m *testing.M) {
+	if path := os.Getenv("PULSENOTE_LOCK_HELPER"); path != "" {
+		if _, err := instance.Acquire(path); err != nil {
+			os.Exit(1)
+		}
+		os.Stdout.WriteString("locked\n")
+		time.Sleep(time.Minute)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestAcquireWritesPID(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.lock")
+
+	lock, err := instance.Acquire(path)
+	if err != nil {
+		t.Fatalf("Erro ao obter lock - %v", err)
+	}
+	defer lock.Release()
+
+	pid, err := instance.ReadPID(path)
+	if err != nil {
+		t.Fatalf("Erro ao ler PID - %v", err)
+	}
+	if pid != os.Getpid() {
+		t.Errorf("PID esperado %d, obtido %d", os.Getpid(), pid)
+	}
+}
+
+func TestAcquireTwiceFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.lock")
+
+	lock, err := instance.Acquire(path)
+	if err != nil {
+		t.Fatalf("Erro ao obter lock - %v", err)
+	}
+
+	if _, err := instance.Acquire(path); !errors.Is(err, instance.ErrAlreadyRunning) {
+		t.Errorf("Esperado ErrAlreadyRunning, obtido %v", err)
+	}
+
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Erro ao liberar lock - %v", err)
+	}
+	lock, err = instance.Acquire(path)
+	if err != nil {
+		t.Fatalf("Lock liberado deveria poder ser obtido de novo - %v", err)
+	}
+	lock.Release()
+}
+
+func TestReleaseKeepsFileWithoutPID(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.lock")
+
+	lock, err := instance.Acquire(path)
+	if err != nil {
+		t.Fatalf("Erro ao obter lock - %v", err)
+	}
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Erro ao liberar lock - %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Arquivo de lock não deveria ser removido - %v", err)
+	}
+	if len(data) != 0 {
+		t.Errorf("PID deveria ser apagado, arquivo contém %q", data)
+	}
+}
+
+func TestAcquireIgnoresStaleFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.lock")
+	if err := os.WriteFile(path, []byte("999999"), 0644); err != nil {
+		t.Fatalf("Erro ao criar lock abandonado - %v", err)
+	}
+
+	lock, err := instance.Acquire(path)
+	if err != nil {
+		t.Fatalf("Arquivo sem lock ativo não deveria bloquear - %v", err)
+	}
+	defer lock.Release()
+
+	if pid, _ := instance.ReadPID(path); pid != os.Getpid() {
+		t.Errorf("PID antigo não foi substituído: %d", pid)
+	}
+}
+
+func TestTerminateWithoutServer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.lock")
+	if err := instance.Terminate(path); !errors.Is(err, instance.ErrNotRunning) {
+		t.Errorf("Esperado ErrNotRunning, obtido %v", err)
+	}
+}
+
+func TestTerminateStopsLockOwner(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.lock")
+
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	cmd.Env = append(os.Environ(), "PULSENOTE_LOCK_HELPER="+path)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatalf("Erro ao criar pipe - %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Erro ao iniciar processo auxiliar - %v", err)
+	}
+	defer cmd.Process.Kill()
+
+	buf := make([]byte, len("locked\n"))
+	if _, err := stdout.Read(buf); err != nil {
+		t.Fatalf("Processo auxiliar não obteve o lock - %v", err)
+	}
+
+	if err := instance.Terminate(path); err != nil {
+		t.Fatalf("Erro ao finalizar dono do lock - %v", err)
+	}
+
+	exited := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(exited)
+	}()
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Processo dono do lock não foi finalizado")
+	}
+}
@@ -0,0 +1,29 @@
+//go:build windows
+
+package instance
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+var errLocked = windows.ERROR_LOCK_VIOLATION
+
+// O Windows impede a leitura de regiões travadas, então o lock é feito em um
+// byte muito além do conteúdo para que ReadPID continue funcionando.
+const lockOffset = 0x7fffffff
+
+func lockFile(f *os.File) error {
+	ol := windows.Overlapped{Offset: lockOffset}
+	return windows.LockFileEx(
+		windows.Handle(f.Fd()),
+		windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY,
+		0, 1, 0, &ol,
+	)
+}
+
+func unlockFile(f *os.File) error {
+	ol := windows.Overlapped{Offset: lockOffset}
+	return windows.UnlockFileEx(windows.Handle(f.Fd()), 0, 1, 0, &ol)
+}
@@ -0,0 +1,179 @@
+// Package ipc implementa o canal de controle local entre server e client: um
+// socket de domínio Unix na pasta de dados, com uma requisição e uma resposta
+// JSON (uma linha cada) por conexão.
+package ipc
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gustavo-silva98/adnotes/internal/repository/file"
+)
+
+// Comandos aceitos pelo server.
+const (
+	CmdPing         = "ping"
+	CmdShutdown     = "shutdown"
+	CmdOpenState    = "open-state"
+	CmdReloadConfig = "reload-config"
+)
+
+// DialTimeout limita a espera por conexão e resposta do server.
+const DialTimeout = 2 * time.Second
+
+// ErrAlreadyServing indica que já existe um server respondendo no socket.
+var ErrAlreadyServing = errors.New("já existe um server escutando no socket de controle")
+
+// Request é uma chamada ao server. Args depende do comando; em open-state,
+// Args[0] é o estado do client e o restante são seus argumentos.
+type Request struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+}
+
+// Response é a resposta do server a uma Request.
+type Response struct {
+	OK      bool   `json:"ok"`
+	Message string `json:"message,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// HandlerFunc trata uma Request recebida pelo server.
+type HandlerFunc func(req Request) Response
+
+// SocketPath retorna o caminho padrão do socket de controle.
+func SocketPath() string {
+	return file.DataPath("pulsenote.sock")
+}
+
+// Server escuta o socket de controle e despacha as requisições por comando.
+type Server struct {
+	path     string
+	listener net.Listener
+	mu       sync.Mutex
+	handlers map[string]HandlerFunc
+	conns    sync.WaitGroup
+}
+
+// Listen cria o socket em path. Um arquivo de socket abandonado (server
+// anterior encerrado sem limpeza) é removido; se outro server responder no
+// mesmo caminho, retorna ErrAlreadyServing.
+func Listen(path string) (*Server, error) {
+	if _, err := os.Stat(path); err == nil {
+		if _, err := Call(path, Request{Command: CmdPing}); err == nil {
+			return nil, ErrAlreadyServing
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("erro ao remover socket antigo: %v", err)
+		}
+	}
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar socket de controle: %v", err)
+	}
+	return &Server{
+		path:     path,
+		listener: l,
+		handlers: map[string]HandlerFunc{},
+	}, nil
+}
+
+// Handle registra o tratamento de um comando.
+func (s *Server) Handle(command string, h HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[command] = h
+}
+
+// Serve aceita conexões até Close ser chamado.
+func (s *Server) Serve() error {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		s.conns.Add(1)
+		go func() {
+			defer s.conns.Done()
+			s.serveConn(conn)
+		}()
+	}
+}
+
+// Close para de aceitar conexões, espera as respostas em andamento e remove o socket.
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.conns.Wait()
+	os.Remove(s.path)
+	return err
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(DialTimeout))
+
+	var req Request
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err == nil {
+		err = json.Unmarshal(line, &req)
+	}
+
+	var resp Response
+	if err != nil {
+		resp = Response{Error: fmt.Sprintf("requisição inválida: %v", err)}
+	} else {
+		s.mu.Lock()
+		h, ok := s.handlers[req.Command]
+		s.mu.Unlock()
+		if ok {
+			resp = h(req)
+		} else {
+			resp = Response{Error: fmt.Sprintf("comando desconhecido: %q", req.Command)}
+		}
+	}
+
+	out, _ := json.Marshal(resp)
+	conn.Write(append(out, '\n'))
+}
+
+// Call envia req ao server em path e espera a resposta. Uma Response com
+// OK false é convertida em erro.
+func Call(path string, req Request) (Response, error) {
+	conn, err := net.DialTimeout("unix", path, DialTimeout)
+	if err != nil {
+		return Response{}, fmt.Errorf("server não está respondendo: %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(DialTimeout))
+
+	out, err := json.Marshal(req)
+	if err != nil {
+		return Response{}, err
+	}
+	if _, err := conn.Write(append(out, '\n')); err != nil {
+		return Response{}, fmt.Errorf("erro ao enviar comando %q: %v", req.Command, err)
+	}
+
+	var resp Response
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		return Response{}, fmt.Errorf("erro ao ler resposta do comando %q: %v", req.Command, err)
+	}
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return Response{}, fmt.Errorf("resposta inválida do server: %v", err)
+	}
+	if !resp.OK {
+		return resp, errors.New(resp.Error)
+	}
+	return resp, nil
+}
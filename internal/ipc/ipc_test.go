@@ -0,0 +1,112 @@
+package ipc_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gustavo-silva98/adnotes/internal/ipc"
+)
+
+func startServer(t *testing.T) (*ipc.Server, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.sock")
+	srv, err := ipc.Listen(path)
+	if err != nil {
+		t.Fatalf("Erro ao criar server IPC - %v", err)
+	}
+	go srv.Serve()
+	t.Cleanup(func() { srv.Close() })
+	return srv, path
+}
+
+func TestCallPing(t *testing.T) {
+	srv, path := startServer(t)
+	srv.Handle(ipc.CmdPing, func(req ipc.Request) ipc.Response {
+		return ipc.Response{OK: true, Message: "pong"}
+	})
+
+	resp, err := ipc.Call(path, ipc.Request{Command: ipc.CmdPing})
+	if err != nil {
+		t.Fatalf("Erro no ping - %v", err)
+	}
+	if resp.Message != "pong" {
+		t.Errorf("Resposta esperada pong, obtida %q", resp.Message)
+	}
+}
+
+func TestCallPassesArgs(t *testing.T) {
+	srv, path := startServer(t)
+	var got []string
+	srv.Handle(ipc.CmdOpenState, func(req ipc.Request) ipc.Response {
+		got = req.Args
+		return ipc.Response{OK: true}
+	})
+
+	if _, err := ipc.Call(path, ipc.Request{Command: ipc.CmdOpenState, Args: []string{"Reminder", "7"}}); err != nil {
+		t.Fatalf("Erro no open-state - %v", err)
+	}
+	if len(got) != 2 || got[0] != "Reminder" || got[1] != "7" {
+		t.Errorf("Argumentos recebidos divergentes: %v", got)
+	}
+}
+
+func TestCallUnknownCommand(t *testing.T) {
+	_, path := startServer(t)
+
+	resp, err := ipc.Call(path, ipc.Request{Command: "nao-existe"})
+	if err == nil {
+		t.Fatal("Esperado erro para comando desconhecido")
+	}
+	if resp.OK {
+		t.Error("Resposta não deveria ser OK")
+	}
+}
+
+func TestCallHandlerError(t *testing.T) {
+	srv, path := startServer(t)
+	srv.Handle(ipc.CmdReloadConfig, func(req ipc.Request) ipc.Response {
+		return ipc.Response{Error: "config inválida"}
+	})
+
+	_, err := ipc.Call(path, ipc.Request{Command: ipc.CmdReloadConfig})
+	if err == nil || err.Error() != "config inválida" {
+		t.Errorf("Esperado erro do handler, obtido %v", err)
+	}
+}
+
+func TestCallWithoutServer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nada.sock")
+	if _, err := ipc.Call(path, ipc.Request{Command: ipc.CmdPing}); err == nil {
+		t.Error("Esperado erro sem server escutando")
+	}
+}
+
+func TestListenRefusesLiveSocket(t *testing.T) {
+	srv, path := startServer(t)
+	srv.Handle(ipc.CmdPing, func(req ipc.Request) ipc.Response {
+		return ipc.Response{OK: true}
+	})
+
+	if _, err := ipc.Listen(path); !errors.Is(err, ipc.ErrAlreadyServing) {
+		t.Errorf("Esperado ErrAlreadyServing, obtido %v", err)
+	}
+}
+
+func TestListenReplacesStaleSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stale.sock")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatalf("Erro ao criar socket abandonado - %v", err)
+	}
+
+	srv, err := ipc.Listen(path)
+	if err != nil {
+		t.Fatalf("Socket abandonado deveria ser substituído - %v", err)
+	}
+	srv.Close()
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Close deveria remover o arquivo do socket")
+	}
+}
@@ -0,0 +1,68 @@
+// Package notetitle monta o título exibido das notas: o título explícito,
+// quando existe, ou um título derivado do próprio texto.
+package notetitle
+
+import (
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// ellipsis marca os títulos cortados.
+const ellipsis = "…"
+
+// Derive extrai o título do texto: o primeiro cabeçalho Markdown ("# Título")
+// ou, se não houver, a primeira linha não vazia. Espaços repetidos viram um só.
+func Derive(text string) string {
+	first := ""
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if heading, ok := atxHeading(line); ok {
+			return heading
+		}
+		if first == "" {
+			first = strings.Join(strings.Fields(line), " ")
+		}
+	}
+	return first
+}
+
+// atxHeading reconhece um cabeçalho "# texto" a "###### texto", removendo os
+// "#" de fechamento opcionais. "#tag" sem espaço não é cabeçalho.
+func atxHeading(line string) (string, bool) {
+	level := len(line) - len(strings.TrimLeft(line, "#"))
+	if level == 0 || level > 6 {
+		return "", false
+	}
+	rest := line[level:]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return "", false
+	}
+	rest = strings.TrimSpace(strings.TrimRight(strings.TrimSpace(rest), "#"))
+	if rest == "" {
+		return "", false
+	}
+	return strings.Join(strings.Fields(rest), " "), true
+}
+
+// Truncate corta s para caber em width colunas de terminal, sem partir
+// caracteres multibyte e terminando em "…" quando há corte.
+func Truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	return runewidth.Truncate(s, width, ellipsis)
+}
+
+// Of devolve o título da nota em até width colunas: title, se preenchido, ou
+// o título derivado de text.
+func Of(title, text string, width int) string {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		title = Derive(text)
+	}
+	return Truncate(title, width)
+}
@@ -0,0 +1,58 @@
+package notetitle_test
+
+import (
+	"testing"
+
+	"github.com/gustavo-silva98/adnotes/internal/notetitle"
+	"github.com/mattn/go-runewidth"
+)
+
+func TestDerive(t *testing.T) {
+	tests := []struct {
+		text, want string
+	}{
+		{"comprar pão, leite e café\nsegunda linha", "comprar pão, leite e café"},
+		{"\n\n  reunião   de   equipe  \n", "reunião de equipe"},
+		{"intro\n## Ata da reunião ##\ncorpo", "Ata da reunião"},
+		{"#projeto é tag, não cabeçalho", "#projeto é tag, não cabeçalho"},
+		{"####### sete\nlinha", "####### sete"},
+		{"#\n# Título", "Título"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := notetitle.Derive(tt.text); got != tt.want {
+			t.Errorf("Derive(%q) = %q, esperado %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"ação", 10, "ação"},
+		{"configuração", 8, "configu…"},
+		{"日本語のノート", 7, "日本語…"},
+		{"texto", 0, ""},
+	}
+	for _, tt := range tests {
+		got := notetitle.Truncate(tt.s, tt.width)
+		if got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, esperado %q", tt.s, tt.width, got, tt.want)
+		}
+		if runewidth.StringWidth(got) > tt.width {
+			t.Errorf("Truncate(%q, %d) passou da largura: %q", tt.s, tt.width, got)
+		}
+	}
+}
+
+func TestOfPrefersExplicitTitle(t *testing.T) {
+	if got := notetitle.Of("  Runbook de plantão ", "# Outro", 40); got != "Runbook de plantão" {
+		t.Errorf("Título explícito ignorado: %q", got)
+	}
+	if got := notetitle.Of("", "# Ata, revisão\ncorpo", 40); got != "Ata, revisão" {
+		t.Errorf("Título derivado inesperado: %q", got)
+	}
+}
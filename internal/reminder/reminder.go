@@ -0,0 +1,353 @@
+// Package reminder interpreta as expressões de lembrete digitadas pelo usuário,
+// como "in 2h", "tomorrow 9:00" e "every monday".
+package reminder
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultHour é o horário usado quando a expressão informa só o dia.
+const DefaultHour = 9
+
+const (
+	day  = 24 * time.Hour
+	week = 7 * day
+)
+
+// ErrInvalid é retornado quando a expressão não é reconhecida.
+var ErrInvalid = errors.New("lembrete inválido")
+
+// Schedule é o resultado de uma expressão: o próximo disparo e o intervalo de
+// repetição (zero para lembretes únicos). Um Schedule zero significa sem lembrete.
+type Schedule struct {
+	At    time.Time
+	Every time.Duration
+}
+
+// IsZero indica ausência de lembrete.
+func (s Schedule) IsZero() bool { return s.At.IsZero() }
+
+// Reminder retorna At em segundos unix, no formato de file.Note.Reminder.
+func (s Schedule) Reminder() int {
+	if s.IsZero() {
+		return 0
+	}
+	return int(s.At.Unix())
+}
+
+// PlusReminder retorna Every em segundos, no formato de file.Note.PlusReminder.
+func (s Schedule) PlusReminder() int { return int(s.Every / time.Second) }
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday, "domingo": time.Sunday, "dom": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday, "segunda": time.Monday, "seg": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "terça": time.Tuesday, "terca": time.Tuesday, "ter": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday, "quarta": time.Wednesday, "qua": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "quinta": time.Thursday, "qui": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday, "sexta": time.Friday, "sex": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday, "sábado": time.Saturday, "sabado": time.Saturday, "sab": time.Saturday,
+}
+
+// Sinônimos em português são normalizados para a forma em inglês antes do parse.
+var synonyms = map[string]string{
+	"em":     "in",
+	"daqui":  "in",
+	"hoje":   "today",
+	"amanhã": "tomorrow",
+	"amanha": "tomorrow",
+	"toda":   "every",
+	"todo":   "every",
+	"todos":  "every",
+	"todas":  "every",
+	"cada":   "every",
+	"às":     "at",
+	"as":     "at",
+	"dia":    "day",
+	"semana": "week",
+	"hora":   "hour",
+	"nenhum": "off",
+}
+
+var units = map[string]time.Duration{
+	"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute, "minuto": time.Minute, "minutos": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hrs": time.Hour, "hour": time.Hour, "hours": time.Hour, "horas": time.Hour,
+	"d": day, "day": day, "days": day, "dias": day,
+	"w": week, "week": week, "weeks": week, "semanas": week,
+}
+
+// Parse interpreta input relativo a now. Formatos aceitos:
+//
+//	in 2h | in 30 minutes | in 1h30m
+//	today 18:00 | tomorrow | tomorrow 9:00 | 14:30
+//	monday | friday 17:00
+//	every monday | every day 8:00 | every 2h | daily | weekly
+//	2025-10-20 | 2025-10-20 14:00 | 20/10/2025 14:00
+//	off | none (remove o lembrete)
+//
+// Sinônimos em português ("em 2h", "amanhã 9:00", "toda segunda") também são aceitos.
+func Parse(input string, now time.Time) (Schedule, error) {
+	fields := normalize(input)
+	if len(fields) == 0 {
+		return Schedule{}, fmt.Errorf("%w: expressão vazia", ErrInvalid)
+	}
+
+	switch fields[0] {
+	case "off", "none", "no":
+		if len(fields) == 1 {
+			return Schedule{}, nil
+		}
+	case "in":
+		d, err := parseDuration(fields[1:])
+		if err != nil {
+			return Schedule{}, err
+		}
+		return Schedule{At: now.Add(d).Truncate(time.Second)}, nil
+	case "every", "daily", "weekly", "hourly":
+		return parseEvery(fields, now)
+	}
+
+	at, err := parseMoment(fields, now)
+	if err != nil {
+		return Schedule{}, err
+	}
+	return Schedule{At: at}, nil
+}
+
+func normalize(input string) []string {
+	var out []string
+	for _, f := range strings.Fields(strings.ToLower(input)) {
+		if s, ok := synonyms[f]; ok {
+			f = s
+		}
+		if f == "at" {
+			continue
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+func parseEvery(fields []string, now time.Time) (Schedule, error) {
+	switch fields[0] {
+	case "daily":
+		fields = append([]string{"every", "day"}, fields[1:]...)
+	case "weekly":
+		fields = append([]string{"every", "week"}, fields[1:]...)
+	case "hourly":
+		fields = append([]string{"every", "hour"}, fields[1:]...)
+	}
+	rest := fields[1:]
+	if len(rest) == 0 {
+		return Schedule{}, fmt.Errorf("%w: informe o intervalo após \"every\"", ErrInvalid)
+	}
+
+	if wd, ok := weekdays[rest[0]]; ok {
+		at, err := nextWeekday(wd, rest[1:], now)
+		if err != nil {
+			return Schedule{}, err
+		}
+		return Schedule{At: at, Every: week}, nil
+	}
+
+	switch rest[0] {
+	case "day":
+		at, err := nextClock(rest[1:], now)
+		if err != nil {
+			return Schedule{}, err
+		}
+		return Schedule{At: at, Every: day}, nil
+	case "week":
+		at, err := nextWeekday(now.Weekday(), rest[1:], now)
+		if err != nil {
+			return Schedule{}, err
+		}
+		return Schedule{At: at, Every: week}, nil
+	case "hour":
+		if len(rest) > 1 {
+			return Schedule{}, fmt.Errorf("%w: %q", ErrInvalid, strings.Join(rest, " "))
+		}
+		return Schedule{At: now.Add(time.Hour).Truncate(time.Second), Every: time.Hour}, nil
+	}
+
+	d, err := parseDuration(rest)
+	if err != nil {
+		return Schedule{}, err
+	}
+	return Schedule{At: now.Add(d).Truncate(time.Second), Every: d}, nil
+}
+
+// parseMoment trata lembretes únicos com data ou dia da semana.
+func parseMoment(fields []string, now time.Time) (time.Time, error) {
+	switch fields[0] {
+	case "today":
+		t, err := clockOn(now, fields[1:], now.Hour()+1)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if !t.After(now) {
+			return time.Time{}, fmt.Errorf("%w: horário de hoje já passou", ErrInvalid)
+		}
+		return t, nil
+	case "tomorrow":
+		return clockOn(now.AddDate(0, 0, 1), fields[1:], DefaultHour)
+	}
+
+	if wd, ok := weekdays[fields[0]]; ok {
+		return nextWeekday(wd, fields[1:], now)
+	}
+
+	if date, ok := parseDate(fields[0], now.Location()); ok {
+		return clockOn(date, fields[1:], DefaultHour)
+	}
+
+	if len(fields) == 1 {
+		if _, _, ok := parseClock(fields[0]); ok {
+			return nextClock(fields, now)
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: %q", ErrInvalid, strings.Join(fields, " "))
+}
+
+// nextClock retorna o próximo instante com o horário informado, hoje ou amanhã.
+func nextClock(rest []string, now time.Time) (time.Time, error) {
+	t, err := clockOn(now, rest, DefaultHour)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !t.After(now) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// nextWeekday retorna a próxima ocorrência de wd (estritamente após now).
+func nextWeekday(wd time.Weekday, rest []string, now time.Time) (time.Time, error) {
+	delta := (int(wd) - int(now.Weekday()) + 7) % 7
+	t, err := clockOn(now.AddDate(0, 0, delta), rest, DefaultHour)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !t.After(now) {
+		t = t.AddDate(0, 0, 7)
+	}
+	return t, nil
+}
+
+// clockOn aplica o horário de rest (ou defaultHour:00) à data de base.
+func clockOn(base time.Time, rest []string, defaultHour int) (time.Time, error) {
+	hour, minute := defaultHour, 0
+	switch len(rest) {
+	case 0:
+	case 1:
+		h, m, ok := parseClock(rest[0])
+		if !ok {
+			return time.Time{}, fmt.Errorf("%w: horário %q", ErrInvalid, rest[0])
+		}
+		hour, minute = h, m
+	default:
+		return time.Time{}, fmt.Errorf("%w: %q", ErrInvalid, strings.Join(rest, " "))
+	}
+	y, mo, d := base.Date()
+	return time.Date(y, mo, d, hour, minute, 0, 0, base.Location()), nil
+}
+
+// parseClock aceita "9", "9:00", "09:30", "9h", "9h30", "9am" e "5pm". Com
+// am/pm a hora vai de 1 a 12, e "12am" é meia-noite.
+func parseClock(s string) (hour, minute int, ok bool) {
+	meridiem, pm := false, false
+	switch {
+	case strings.HasSuffix(s, "am"):
+		s, meridiem = strings.TrimSuffix(s, "am"), true
+	case strings.HasSuffix(s, "pm"):
+		s, meridiem, pm = strings.TrimSuffix(s, "pm"), true, true
+	}
+	s = strings.Replace(s, "h", ":", 1)
+	s = strings.TrimSuffix(s, ":")
+
+	hs, ms, hasMinute := strings.Cut(s, ":")
+	hour, err := strconv.Atoi(hs)
+	if err != nil {
+		return 0, 0, false
+	}
+	if hasMinute {
+		if minute, err = strconv.Atoi(ms); err != nil || len(ms) != 2 {
+			return 0, 0, false
+		}
+	}
+	if meridiem {
+		if hour < 1 || hour > 12 {
+			return 0, 0, false
+		}
+		hour %= 12
+		if pm {
+			hour += 12
+		}
+	}
+	if hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return 0, 0, false
+	}
+	return hour, minute, true
+}
+
+func parseDate(s string, loc *time.Location) (time.Time, bool) {
+	for _, layout := range []string{"2006-01-02", "02/01/2006", "2/1/2006"} {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseDuration aceita "2h", "1h30m", "2 hours", "30 min" e "3d".
+func parseDuration(fields []string) (time.Duration, error) {
+	s := strings.Join(fields, "")
+	if s == "" {
+		return 0, fmt.Errorf("%w: informe uma duração", ErrInvalid)
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return d, nil
+	}
+
+	// Separa o número da unidade: "2hours", "30min", "3d".
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	n, err := strconv.Atoi(s[:i])
+	unit, ok := units[s[i:]]
+	if err != nil || !ok || n <= 0 {
+		return 0, fmt.Errorf("%w: duração %q", ErrInvalid, strings.Join(fields, " "))
+	}
+	return time.Duration(n) * unit, nil
+}
+
+// Describe formata um lembrete para exibição, por exemplo "seg 20/10 09:00 ↻ 7d".
+func Describe(reminder, plusReminder int) string {
+	if reminder <= 0 {
+		return ""
+	}
+	at := time.Unix(int64(reminder), 0)
+	out := fmt.Sprintf("%v %v", weekdayShort[at.Weekday()], at.Format("02/01 15:04"))
+	if plusReminder > 0 {
+		out += " ↻ " + formatInterval(time.Duration(plusReminder)*time.Second)
+	}
+	return out
+}
+
+var weekdayShort = [...]string{"dom", "seg", "ter", "qua", "qui", "sex", "sáb"}
+
+func formatInterval(d time.Duration) string {
+	switch {
+	case d%day == 0:
+		return fmt.Sprintf("%dd", d/day)
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+	return d.String()
+}
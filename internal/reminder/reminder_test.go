@@ -0,0 +1,139 @@
+package reminder_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gustavo-silva98/adnotes/internal/reminder"
+)
+
+// Quarta-feira, 15/10/2025 10:00.
+var now = time.Date(2025, 10, 15, 10, 0, 0, 0, time.UTC)
+
+func at(month time.Month, day, hour, minute int) time.Time {
+	return time.Date(2025, month, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		at    time.Time
+		every time.Duration
+	}{
+		{"in 2h", now.Add(2 * time.Hour), 0},
+		{"in 30 minutes", now.Add(30 * time.Minute), 0},
+		{"in 1h30m", now.Add(90 * time.Minute), 0},
+		{"in 3d", now.AddDate(0, 0, 3), 0},
+		{"em 2 horas", now.Add(2 * time.Hour), 0},
+		{"today 18:00", at(10, 15, 18, 0), 0},
+		{"tomorrow", at(10, 16, 9, 0), 0},
+		{"tomorrow 9:00", at(10, 16, 9, 0), 0},
+		{"tomorrow at 5pm", at(10, 16, 17, 0), 0},
+		{"tomorrow 12am", at(10, 16, 0, 0), 0},
+		{"tomorrow 12pm", at(10, 16, 12, 0), 0},
+		{"tomorrow 12:30am", at(10, 16, 0, 30), 0},
+		{"amanhã 14h30", at(10, 16, 14, 30), 0},
+		{"14:30", at(10, 15, 14, 30), 0},
+		{"8:00", at(10, 16, 8, 0), 0},
+		{"friday 17:00", at(10, 17, 17, 0), 0},
+		{"wednesday", at(10, 22, 9, 0), 0},
+		{"2025-10-20", at(10, 20, 9, 0), 0},
+		{"2025-10-20 14:00", at(10, 20, 14, 0), 0},
+		{"20/10/2025 14:00", at(10, 20, 14, 0), 0},
+		{"every monday", at(10, 20, 9, 0), 7 * 24 * time.Hour},
+		{"every wednesday 11:00", at(10, 15, 11, 0), 7 * 24 * time.Hour},
+		{"toda segunda 8:30", at(10, 20, 8, 30), 7 * 24 * time.Hour},
+		{"every day 8:00", at(10, 16, 8, 0), 24 * time.Hour},
+		{"daily 18:00", at(10, 15, 18, 0), 24 * time.Hour},
+		{"every 2h", now.Add(2 * time.Hour), 2 * time.Hour},
+		{"every hour", now.Add(time.Hour), time.Hour},
+		{"weekly", at(10, 22, 9, 0), 7 * 24 * time.Hour},
+		{"  IN   2H  ", now.Add(2 * time.Hour), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := reminder.Parse(tt.input, now)
+			if err != nil {
+				t.Fatalf("Parse(%q) retornou erro: %v", tt.input, err)
+			}
+			if !got.At.Equal(tt.at) {
+				t.Errorf("Parse(%q).At = %v, esperado %v", tt.input, got.At, tt.at)
+			}
+			if got.Every != tt.every {
+				t.Errorf("Parse(%q).Every = %v, esperado %v", tt.input, got.Every, tt.every)
+			}
+		})
+	}
+}
+
+func TestParseOff(t *testing.T) {
+	for _, input := range []string{"off", "none", "nenhum"} {
+		got, err := reminder.Parse(input, now)
+		if err != nil {
+			t.Fatalf("Parse(%q) retornou erro: %v", input, err)
+		}
+		if !got.IsZero() || got.Reminder() != 0 {
+			t.Errorf("Parse(%q) deveria remover o lembrete, obtido %+v", input, got)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		"in",
+		"in banana",
+		"every",
+		"every someday",
+		"today 8:00",
+		"tomorrow 25:00",
+		"tomorrow 9:7",
+		"tomorrow 13pm",
+		"tomorrow 0am",
+		"monday noon",
+		"soon",
+	}
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			if _, err := reminder.Parse(input, now); !errors.Is(err, reminder.ErrInvalid) {
+				t.Errorf("Parse(%q) esperado ErrInvalid, obtido %v", input, err)
+			}
+		})
+	}
+}
+
+func TestScheduleNoteFields(t *testing.T) {
+	s, err := reminder.Parse("every monday", now)
+	if err != nil {
+		t.Fatalf("Erro no parse: %v", err)
+	}
+	if s.Reminder() != int(at(10, 20, 9, 0).Unix()) {
+		t.Errorf("Reminder() divergente: %d", s.Reminder())
+	}
+	if s.PlusReminder() != 7*24*60*60 {
+		t.Errorf("PlusReminder() divergente: %d", s.PlusReminder())
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	if got := reminder.Describe(0, 0); got != "" {
+		t.Errorf("Describe sem lembrete deveria ser vazio, obtido %q", got)
+	}
+	ts := int(time.Date(2025, 10, 20, 9, 0, 0, 0, time.Local).Unix())
+	tests := []struct {
+		plus int
+		want string
+	}{
+		{0, "seg 20/10 09:00"},
+		{7 * 24 * 60 * 60, "seg 20/10 09:00 ↻ 7d"},
+		{2 * 60 * 60, "seg 20/10 09:00 ↻ 2h"},
+		{90 * 60, "seg 20/10 09:00 ↻ 90m"},
+	}
+	for _, tt := range tests {
+		if got := reminder.Describe(ts, tt.plus); got != tt.want {
+			t.Errorf("Describe(%d, %d) = %q, esperado %q", ts, tt.plus, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package file
+
+import (
+	"strings"
+	"time"
+)
+
+// NoteFilter restringe as notas retornadas por QueryNotes, GetTotalCount e
+// FullSearchNote. O valor zero não filtra nada além da lixeira: notas
+// excluídas nunca entram nessas consultas.
+type NoteFilter struct {
+	// Tag limita o resultado às notas marcadas com essa tag.
+	Tag string
+	// NotebookID limita o resultado às notas do caderno; 0 considera todos.
+	NotebookID int
+	// From e To limitam a data de criação ao intervalo [From, To), em unix;
+	// 0 deixa o lado correspondente aberto.
+	From int64
+	To   int64
+	// Search limita o resultado às notas encontradas pela busca, na sintaxe de
+	// ParseSearchQuery, como nas buscas salvas. Não muda a ordem das notas.
+	Search string
+}
+
+// conditions monta as condições do filtro sobre a nota "n", exigindo também
+// as tags em extraTags.
+func (f NoteFilter) conditions(extraTags ...string) ([]string, []any, error) {
+	if f.Search != "" {
+		q, _ := ParseSearchQuery(f.Search, time.Now())
+		f.Search = ""
+		conds, args, err := q.conditions(f, extraTags...)
+		if err != nil {
+			return nil, nil, err
+		}
+		if q.Match != "" {
+			conds = append(conds, `n.id IN (SELECT rowid FROM notes_fts WHERE notes_fts MATCH ?)`)
+			args = append(args, q.Match)
+		}
+		return conds, args, nil
+	}
+
+	tags := append([]string{}, extraTags...)
+	if f.Tag != "" {
+		name, err := NormalizeTag(f.Tag)
+		if err != nil {
+			return nil, nil, err
+		}
+		tags = append(tags, name)
+	}
+
+	conds, args := tagConditions(tags)
+	conds = append(conds, `n.deleted_at = 0`)
+	if f.NotebookID != 0 {
+		conds = append(conds, `n.notebook_id = ?`)
+		args = append(args, f.NotebookID)
+	}
+	if f.From != 0 {
+		conds = append(conds, `n.created_at >= ?`)
+		args = append(args, f.From)
+	}
+	if f.To != 0 {
+		conds = append(conds, `n.created_at < ?`)
+		args = append(args, f.To)
+	}
+	return conds, args, nil
+}
+
+// whereClause converte as condições em um WHERE, ou em texto vazio se não houver nenhuma.
+func whereClause(conds []string) string {
+	if len(conds) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(conds, " AND ")
+}
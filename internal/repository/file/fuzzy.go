@@ -0,0 +1,284 @@
+package file
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// fuzzyCandidates é quantas notas o índice de trigramas entrega, das mais
+// para as menos parecidas segundo o bm25, para a pontuação por similaridade.
+const fuzzyCandidates = 200
+
+// fuzzyThreshold é a similaridade mínima, de 0 a 1, para que uma palavra da
+// nota case com a buscada e para que a nota entre no resultado.
+const fuzzyThreshold = 0.7
+
+// FuzzySearchNote busca as palavras de argQuery tolerando erros de digitação,
+// como "kuberentes" para "kubernetes", nos títulos e nos textos. O índice de
+// trigramas escolhe as candidatas, que voltam ordenadas pela similaridade
+// média entre as palavras buscadas e as mais parecidas da nota. Qualificadores
+// e exclusões valem como em FullSearchNote; aspas e OR não mudam nada. Sem
+// palavras de ao menos três letras, a busca é a de FullSearchNote.
+func (s SqliteHandler) FuzzySearchNote(ctx context.Context, argQuery string, filter NoteFilter) ([]SearchResult, error) {
+	q, _ := ParseSearchQuery(argQuery, time.Now())
+	words := fuzzyWords(q.Terms)
+	match := trigramQuery(words)
+	if match == "" {
+		return s.FullSearchNote(ctx, argQuery, filter)
+	}
+
+	conds, args, err := q.conditions(filter)
+	if err != nil {
+		return nil, err
+	}
+	conds = append([]string{`notes_trigram MATCH ?`}, conds...)
+	args = append([]any{match}, args...)
+
+	rows, err := s.DB.QueryContext(
+		ctx,
+		`SELECT `+noteColumns+`
+			FROM notes_trigram
+			INNER JOIN notas n ON n.id = notes_trigram.rowid
+			`+whereClause(conds)+`
+			ORDER BY bm25(notes_trigram) LIMIT ?`,
+		append(args, fuzzyCandidates)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type scored struct {
+		SearchResult
+		score float64
+	}
+	var found []scored
+	for rows.Next() {
+		note, err := scanNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		score, matched := scoreNote(note, words)
+		if score < fuzzyThreshold {
+			continue
+		}
+		found = append(found, scored{
+			SearchResult: SearchResult{
+				Note:    note,
+				Snippet: fuzzySnippet(note.NoteText, matched),
+				Matches: slices.Sorted(maps.Keys(matched)),
+			},
+			score: score,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Estável: no empate, vale a ordem do bm25.
+	sort.SliceStable(found, func(i, j int) bool { return found[i].score > found[j].score })
+	results := make([]SearchResult, len(found))
+	for i, f := range found {
+		results[i] = f.SearchResult
+	}
+	return results, nil
+}
+
+// fuzzyWords normaliza as palavras buscadas, sem repetição.
+func fuzzyWords(terms []string) []string {
+	seen := map[string]bool{}
+	var words []string
+	for _, term := range terms {
+		word := foldWord(term)
+		if word != "" && !seen[word] {
+			seen[word] = true
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// trigramQuery monta o MATCH que aceita qualquer trigrama das palavras. As
+// palavras com menos de três letras não têm trigramas e ficam só para a
+// pontuação.
+func trigramQuery(words []string) string {
+	seen := map[string]bool{}
+	var trigrams []string
+	for _, word := range words {
+		runes := []rune(word)
+		for i := 0; i+3 <= len(runes); i++ {
+			trigram := `"` + string(runes[i:i+3]) + `"`
+			if !seen[trigram] {
+				seen[trigram] = true
+				trigrams = append(trigrams, trigram)
+			}
+		}
+	}
+	return strings.Join(trigrams, " OR ")
+}
+
+// scoreNote devolve a similaridade média entre cada palavra buscada e a mais
+// parecida do título e do texto, e as palavras da nota que casaram.
+func scoreNote(note Note, words []string) (float64, map[string]bool) {
+	noteWords := map[string]bool{}
+	for _, word := range termWords(note.Title + " " + note.NoteText) {
+		noteWords[foldWord(word)] = true
+	}
+
+	matched := map[string]bool{}
+	total := 0.0
+	for _, word := range words {
+		best := 0.0
+		for noteWord := range noteWords {
+			sim := wordSimilarity(word, noteWord)
+			if sim >= fuzzyThreshold {
+				matched[noteWord] = true
+			}
+			best = max(best, sim)
+		}
+		total += best
+	}
+	return total / float64(len(words)), matched
+}
+
+// wordSimilarity compara a palavra buscada com uma da nota: 1 menos a
+// distância de edição (com transposições) dividida pelo tamanho da maior. Como
+// a palavra pode estar pela metade, o começo da palavra da nota também conta,
+// valendo um pouco menos.
+func wordSimilarity(query, word string) float64 {
+	if query == word {
+		return 1
+	}
+	a, b := []rune(query), []rune(word)
+	if len(b) < len(a)-len(a)/2 {
+		return 0
+	}
+	sim := 0.0
+	if len(b) <= 2*len(a) {
+		sim = 1 - float64(editDistance(a, b))/float64(max(len(a), len(b)))
+	}
+	if len(b) > len(a) {
+		prefix := 1 - float64(editDistance(a, b[:len(a)]))/float64(len(a))
+		sim = max(sim, prefix*0.9)
+	}
+	return sim
+}
+
+// editDistance é a distância de Damerau-Levenshtein restrita: inserções,
+// remoções, trocas e transposições de letras vizinhas custam 1.
+func editDistance(a, b []rune) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(b)]
+}
+
+// fuzzySnippet monta, como o snippet() do FTS, um trecho de até 12 palavras a
+// partir de pouco antes da primeira palavra que casou, com as palavras que
+// casaram entre SnippetStart e SnippetEnd.
+func fuzzySnippet(text string, matched map[string]bool) string {
+	type span struct {
+		start, end int
+		hit        bool
+	}
+	var spans []span
+	first := -1
+	start := -1
+	addSpan := func(end int) {
+		hit := matched[foldWord(text[start:end])]
+		if hit && first < 0 {
+			first = len(spans)
+		}
+		spans = append(spans, span{start, end, hit})
+	}
+	for i, r := range text {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if isWord && start < 0 {
+			start = i
+		} else if !isWord && start >= 0 {
+			addSpan(i)
+			start = -1
+		}
+	}
+	if start >= 0 {
+		addSpan(len(text))
+	}
+	if len(spans) == 0 {
+		return ""
+	}
+
+	from := max(first-3, 0)
+	to := min(from+12, len(spans))
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	for k := from; k < to; k++ {
+		if k > from {
+			b.WriteString(collapseSpace(text[spans[k-1].end:spans[k].start]))
+		}
+		word := text[spans[k].start:spans[k].end]
+		if spans[k].hit {
+			word = SnippetStart + word + SnippetEnd
+		}
+		b.WriteString(word)
+	}
+	if to < len(spans) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// foldWord deixa a palavra em minúsculas e sem acentos, como os índices.
+func foldWord(word string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(word) {
+		if !unicode.Is(unicode.Mn, r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
+
+// collapseSpace troca cada sequência de espaços e quebras de linha por um
+// espaço.
+func collapseSpace(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			if !space {
+				b.WriteByte(' ')
+			}
+			space = true
+			continue
+		}
+		space = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
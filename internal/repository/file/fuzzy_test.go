@@ -0,0 +1,133 @@
+package file_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gustavo-silva98/adnotes/internal/repository/file"
+)
+
+func TestFuzzySearchNote(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+
+	exact, _ := handler.InsertNote(&file.Note{CreatedAt: 100, NoteText: "Subir o cluster Kubernetes de homologação"}, ctx)
+	near, _ := handler.InsertNote(&file.Note{CreatedAt: 200, NoteText: "Ler sobre kubernete e helm"}, ctx)
+	titled, _ := handler.InsertNote(&file.Note{CreatedAt: 300, Title: "Configuração do Kubernetes", NoteText: "ver pasta infra"}, ctx)
+	handler.InsertNote(&file.Note{CreatedAt: 400, NoteText: "Lista de compras"}, ctx)
+
+	results, err := handler.FuzzySearchNote(ctx, "kuberentes", file.NoteFilter{})
+	if err != nil {
+		t.Fatalf("Erro na busca aproximada: %v", err)
+	}
+	ids := make([]int, len(results))
+	for i, r := range results {
+		ids[i] = r.ID
+	}
+	if len(ids) != 3 || ids[2] != int(near) {
+		t.Fatalf("Resultado = %v, esperado %d e %d antes de %d", ids, exact, titled, near)
+	}
+	for _, r := range results {
+		if r.ID == int(exact) && !strings.Contains(r.Snippet, file.SnippetStart+"Kubernetes"+file.SnippetEnd) {
+			t.Errorf("Trecho deveria marcar a palavra parecida: %q", r.Snippet)
+		}
+		if r.ID == int(titled) && !slices.Equal(r.Matches, []string{"kubernetes"}) {
+			t.Errorf("Palavras casadas = %q", r.Matches)
+		}
+	}
+
+	for _, tc := range []struct {
+		query string
+		want  int
+	}{
+		{"homologacao kubernets", 1},
+		{"configuracao", 1},
+		{"kuberentes -helm", 2},
+		{"kuberentes after:2000-01-01", 0},
+		{"zzzzzz", 0},
+		// Palavras curtas demais para trigramas usam a busca normal.
+		{"de", 2},
+	} {
+		results, err := handler.FuzzySearchNote(ctx, tc.query, file.NoteFilter{})
+		if err != nil || len(results) != tc.want {
+			t.Errorf("%q = %d resultados (%v), esperado %d", tc.query, len(results), err, tc.want)
+		}
+	}
+}
+
+func TestFuzzySearchNoteCapsCandidates(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+	for i := 0; i < 300; i++ {
+		handler.InsertNote(&file.Note{CreatedAt: int64(i), NoteText: fmt.Sprintf("cluster kubernetes %d", i)}, ctx)
+	}
+
+	// O índice de trigramas entrega no máximo 200 candidatas para pontuar em Go;
+	// é isso que mantém o custo da busca limitado em bancos grandes.
+	results, err := handler.FuzzySearchNote(ctx, "kuberentes", file.NoteFilter{})
+	if err != nil {
+		t.Fatalf("Erro na busca: %v", err)
+	}
+	if len(results) != 200 {
+		t.Errorf("Esperadas 200 candidatas, obtidas %d", len(results))
+	}
+}
+
+// BenchmarkFuzzySearchNote mede a busca aproximada em um banco com 50 mil
+// notas de 40 palavras. Em um Xeon de um núcleo, com "go test -bench
+// FuzzySearchNote -benchtime 20x", cada busca levou cerca de 400 ms com erro
+// de digitação e 200 ms com uma palavra comum; o TUI faz a busca fora do
+// Update, então a tela não trava enquanto isso. Quase todo o tempo é o bm25 do
+// índice de trigramas, que pontua toda nota com algum trigrama da busca.
+func BenchmarkFuzzySearchNote(b *testing.B) {
+	handler, err := file.InitDB(":memory:", context.Background())
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer handler.DB.Close()
+	ctx := context.Background()
+
+	vocabulary := strings.Fields(`reunião projeto cliente deploy relatório orçamento kubernetes banco
+		backup agenda contrato fornecedor planilha servidor homologação produção
+		revisão entrega prazo equipe treinamento viagem compras médico escola
+		aluguel imposto investimento leitura curso inglês academia receita`)
+	tx, err := handler.DB.BeginTx(ctx, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	seed := uint32(1)
+	for i := 0; i < 50000; i++ {
+		words := make([]string, 40)
+		for j := range words {
+			seed = seed*1664525 + 1013904223
+			words[j] = vocabulary[int(seed>>16)%len(vocabulary)]
+		}
+		text := fmt.Sprintf("Nota %d: %s", i, strings.Join(words, " "))
+		if _, err := tx.ExecContext(ctx, `INSERT INTO notas (created_at, updated_at, note_text) VALUES (?, ?, ?)`, i, i, text); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		b.Fatal(err)
+	}
+
+	queries := map[string]string{
+		"erro de digitação": "kuberentes relatoro",
+		"palavra comum":     "reuniao",
+	}
+	for name, query := range queries {
+		b.Run(name, func(b *testing.B) {
+			for b.Loop() {
+				if _, err := handler.FuzzySearchNote(ctx, query, file.NoteFilter{}); err != nil {
+					b.Fatal(err)
+				}
+			}
+			perOp := b.Elapsed() / time.Duration(b.N)
+			b.ReportMetric(float64(perOp.Microseconds())/1000, "ms/op")
+		})
+	}
+}
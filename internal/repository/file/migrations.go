@@ -0,0 +1,325 @@
+package file
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ErrSchemaTooNew indica que o banco foi criado por um binário mais novo
+// do que o atual e, portanto, não deve ser aberto.
+var ErrSchemaTooNew = errors.New("schema do banco é mais novo que o suportado por este binário")
+
+// Migration representa um passo de evolução do schema. Cada migração é
+// aplicada uma única vez, em ordem crescente de Version, dentro de uma transação.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(ctx context.Context, tx *sql.Tx) error
+}
+
+// migrations é a lista ordenada de migrações do banco de notas.
+// Novas migrações devem ser adicionadas sempre ao final, com Version sequencial.
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "baseline: tabela notas, notes_fts e triggers",
+		Up:      migrateBaseline,
+	},
+	{
+		Version: 2,
+		Name:    "índice de lembretes pendentes",
+		Up: execStatements(
+			`CREATE INDEX IF NOT EXISTS idx_notas_reminder ON notas(reminder) WHERE reminder > 0`,
+		),
+	},
+	{
+		Version: 3,
+		Name:    "tags e note_tags",
+		Up:      migrateTags,
+	},
+	{
+		Version: 4,
+		Name:    "cadernos",
+		Up:      migrateNotebooks,
+	},
+	{
+		Version: 5,
+		Name:    "histórico de versões das notas",
+		Up:      migrateRevisions,
+	},
+	{
+		Version: 6,
+		Name:    "lixeira",
+		Up: execStatements(
+			`ALTER TABLE notas ADD COLUMN deleted_at INTEGER NOT NULL DEFAULT 0`,
+			`CREATE INDEX idx_notas_deleted ON notas(deleted_at) WHERE deleted_at > 0`,
+		),
+	},
+	{
+		Version: 7,
+		Name:    "created_at e updated_at",
+		Up:      migrateTimestamps,
+	},
+	{
+		Version: 8,
+		Name:    "notas fixadas",
+		Up: execStatements(
+			`ALTER TABLE notas ADD COLUMN pinned INTEGER NOT NULL DEFAULT 0`,
+			`CREATE INDEX idx_notas_pinned ON notas(pinned) WHERE pinned = 1`,
+		),
+	},
+	{
+		Version: 9,
+		Name:    "título das notas",
+		Up:      execStatements(`ALTER TABLE notas ADD COLUMN title TEXT NOT NULL DEFAULT ''`),
+	},
+	{
+		Version: 10,
+		Name:    "busca sem acentos",
+		Up:      migrateFTSDiacritics,
+	},
+	{
+		Version: 11,
+		Name:    "índice de trigramas da busca aproximada",
+		Up: execStatements(
+			`CREATE VIRTUAL TABLE notes_trigram USING fts5(title, note_text, tokenize = 'trigram remove_diacritics 1')`,
+			`INSERT INTO notes_trigram(rowid, title, note_text) SELECT id, title, note_text FROM notas`,
+			`CREATE TRIGGER notes_trigram_ai AFTER INSERT ON notas BEGIN
+				INSERT INTO notes_trigram(rowid, title, note_text) VALUES (new.id, new.title, new.note_text);
+			END`,
+			`CREATE TRIGGER notes_trigram_ad AFTER DELETE ON notas BEGIN
+				DELETE FROM notes_trigram WHERE rowid = old.id;
+			END`,
+			`CREATE TRIGGER notes_trigram_au AFTER UPDATE OF title, note_text ON notas BEGIN
+				DELETE FROM notes_trigram WHERE rowid = old.id;
+				INSERT INTO notes_trigram(rowid, title, note_text) VALUES (new.id, new.title, new.note_text);
+			END`,
+		),
+	},
+	{
+		Version: 12,
+		Name:    "buscas salvas",
+		Up: execStatements(
+			`CREATE TABLE saved_searches (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+				query TEXT NOT NULL
+			)`,
+		),
+	},
+}
+
+// LatestSchemaVersion retorna a versão de schema esperada por este binário.
+func LatestSchemaVersion() int {
+	return migrations[len(migrations)-1].Version
+}
+
+// SchemaVersion lê a versão de schema gravada no banco (PRAGMA user_version).
+// Bancos anteriores ao sistema de migrações retornam 0.
+func SchemaVersion(ctx context.Context, db *sql.DB) (int, error) {
+	var version int
+	if err := db.QueryRowContext(ctx, `PRAGMA user_version`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("erro ao ler versão do schema: %v", err)
+	}
+	return version, nil
+}
+
+// Migrate aplica no banco todas as migrações de list com versão maior que a
+// gravada. Cada migração roda em sua própria transação junto com a atualização
+// da versão, então uma falha não deixa o banco em estado intermediário.
+func Migrate(ctx context.Context, db *sql.DB, list []Migration) error {
+	current, err := SchemaVersion(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	latest := 0
+	for i, m := range list {
+		if m.Version != i+1 {
+			return fmt.Errorf("migração %q fora de ordem: versão %d na posição %d", m.Name, m.Version, i+1)
+		}
+		latest = m.Version
+	}
+	if current > latest {
+		return fmt.Errorf("%w: banco na versão %d, binário suporta até %d", ErrSchemaTooNew, current, latest)
+	}
+
+	for _, m := range list {
+		if m.Version <= current {
+			continue
+		}
+		if err := applyMigration(ctx, db, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyMigration(ctx context.Context, db *sql.DB, m Migration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar migração %d: %v", m.Version, err)
+	}
+	defer tx.Rollback()
+
+	if err := m.Up(ctx, tx); err != nil {
+		return fmt.Errorf("erro ao aplicar migração %d (%v): %w", m.Version, m.Name, err)
+	}
+	// PRAGMA não aceita parâmetros; Version é sempre um inteiro controlado pelo código.
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`PRAGMA user_version = %d`, m.Version)); err != nil {
+		return fmt.Errorf("erro ao gravar versão %d do schema: %v", m.Version, err)
+	}
+	return tx.Commit()
+}
+
+// execStatements cria uma migração que apenas executa os comandos em ordem.
+func execStatements(stmts ...string) func(ctx context.Context, tx *sql.Tx) error {
+	return func(ctx context.Context, tx *sql.Tx) error {
+		for _, stmt := range stmts {
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// migrateBaseline cria o schema original. Usa IF NOT EXISTS para que bancos
+// criados antes das migrações (user_version = 0) sejam adotados sem perda.
+func migrateBaseline(ctx context.Context, tx *sql.Tx) error {
+	return execStatements(
+		`CREATE TABLE IF NOT EXISTS notas (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			hour INTEGER NOT NULL,
+			note_text TEXT NOT NULL,
+			reminder INTEGER,
+			plusreminder INTEGER
+		)`,
+		createFTSTableQuery,
+		triggerInsertQuery,
+		triggerDeleteQuery,
+		triggerUpdateQuery,
+	)(ctx, tx)
+}
+
+// migrateTags cria as tabelas de tags e marca as notas existentes com as
+// "#tag" já escritas no texto. inline indica que a associação veio do texto
+// e deve acompanhar as edições da nota.
+func migrateTags(ctx context.Context, tx *sql.Tx) error {
+	err := execStatements(
+		`CREATE TABLE tags (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE
+		)`,
+		`CREATE TABLE note_tags (
+			note_id INTEGER NOT NULL,
+			tag_id INTEGER NOT NULL,
+			inline INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (note_id, tag_id)
+		)`,
+		`CREATE INDEX idx_note_tags_tag ON note_tags(tag_id)`,
+		`CREATE TRIGGER notes_tags_ad AFTER DELETE ON notas BEGIN
+			DELETE FROM note_tags WHERE note_id = old.id;
+		END`,
+	)(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT id, note_text FROM notas WHERE note_text LIKE '%#%'`)
+	if err != nil {
+		return err
+	}
+	type pending struct {
+		id   int64
+		text string
+	}
+	var notes []pending
+	for rows.Next() {
+		var n pending
+		if err := rows.Scan(&n.id, &n.text); err != nil {
+			rows.Close()
+			return err
+		}
+		notes = append(notes, n)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, n := range notes {
+		if err := syncInlineTags(ctx, tx, n.id, n.text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateNotebooks cria os cadernos e coloca todas as notas existentes no Inbox.
+// parent_id usa 0 em vez de NULL para que UNIQUE valha também no primeiro nível.
+func migrateNotebooks(ctx context.Context, tx *sql.Tx) error {
+	return execStatements(
+		`CREATE TABLE notebooks (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			parent_id INTEGER NOT NULL DEFAULT 0,
+			UNIQUE (parent_id, name)
+		)`,
+		fmt.Sprintf(`INSERT INTO notebooks (id, name, parent_id) VALUES (%d, 'Inbox', 0)`, InboxNotebookID),
+		fmt.Sprintf(`ALTER TABLE notas ADD COLUMN notebook_id INTEGER NOT NULL DEFAULT %d`, InboxNotebookID),
+		`CREATE INDEX idx_notas_notebook ON notas(notebook_id, id)`,
+	)(ctx, tx)
+}
+
+// migrateRevisions guarda o texto anterior de cada edição. O trigger só dispara
+// quando o texto muda, então mover a nota ou trocar o lembrete não gera versão.
+func migrateRevisions(ctx context.Context, tx *sql.Tx) error {
+	return execStatements(
+		`CREATE TABLE note_revisions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			note_id INTEGER NOT NULL,
+			hour INTEGER NOT NULL,
+			note_text TEXT NOT NULL
+		)`,
+		`CREATE INDEX idx_note_revisions_note ON note_revisions(note_id, id)`,
+		`CREATE TRIGGER notes_revisions_au AFTER UPDATE OF note_text ON notas
+			WHEN old.note_text IS NOT new.note_text BEGIN
+			INSERT INTO note_revisions (note_id, hour, note_text) VALUES (old.id, old.hour, old.note_text);
+		END`,
+		`CREATE TRIGGER notes_revisions_ad AFTER DELETE ON notas BEGIN
+			DELETE FROM note_revisions WHERE note_id = old.id;
+		END`,
+	)(ctx, tx)
+}
+
+// migrateTimestamps separa a data de criação da data de edição. hour, que era
+// regravada a cada edição, vira updated_at; created_at vem da versão mais antiga
+// no histórico ou, sem histórico, da própria hour. O SQLite ajusta os triggers
+// que citam a coluna renomeada.
+func migrateTimestamps(ctx context.Context, tx *sql.Tx) error {
+	return execStatements(
+		`ALTER TABLE notas RENAME COLUMN hour TO updated_at`,
+		`ALTER TABLE notas ADD COLUMN created_at INTEGER NOT NULL DEFAULT 0`,
+		`UPDATE notas SET created_at = COALESCE(
+			(SELECT MIN(r.hour) FROM note_revisions r WHERE r.note_id = notas.id),
+			updated_at
+		)`,
+		`CREATE INDEX idx_notas_created ON notas(created_at)`,
+		`CREATE INDEX idx_notas_updated ON notas(updated_at)`,
+	)(ctx, tx)
+}
+
+// migrateFTSDiacritics recria notes_fts com o tokenizador unicode61 ignorando
+// acentos, para que "anotacao" encontre "anotação", e reindexa todas as notas.
+// Os triggers da baseline continuam valendo, já que referenciam a tabela pelo
+// nome.
+func migrateFTSDiacritics(ctx context.Context, tx *sql.Tx) error {
+	return execStatements(
+		`DROP TABLE notes_fts`,
+		`CREATE VIRTUAL TABLE notes_fts USING fts5(note_text_fts, tokenize = 'unicode61 remove_diacritics 2')`,
+		`INSERT INTO notes_fts(rowid, note_text_fts) SELECT id, note_text FROM notas`,
+	)(ctx, tx)
+}
@@ -0,0 +1,192 @@
+package file_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gustavo-silva98/adnotes/internal/repository/file"
+)
+
+// newBaselineFixture cria um banco em disco com o schema anterior às migrações.
+func newBaselineFixture(t *testing.T) string {
+	t.Helper()
+	schema, err := os.ReadFile(filepath.Join("testdata", "baseline_schema.sql"))
+	if err != nil {
+		t.Fatalf("Erro ao ler fixture do schema baseline - %v", err)
+	}
+
+	dbPath := filepath.Join(t.TempDir(), "baseline.db")
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("Erro ao criar banco fixture - %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(string(schema)); err != nil {
+		t.Fatalf("Erro ao aplicar schema baseline - %v", err)
+	}
+	return dbPath
+}
+
+func TestInitDBAppliesAllMigrations(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+
+	version, err := file.SchemaVersion(ctx, handler.DB)
+	if err != nil {
+		t.Fatalf("Erro ao ler versão do schema - %v", err)
+	}
+	if version != file.LatestSchemaVersion() {
+		t.Errorf("Versão esperada %d, obtida %d", file.LatestSchemaVersion(), version)
+	}
+}
+
+func TestMigrateUpgradesBaselineFixture(t *testing.T) {
+	ctx := context.Background()
+	dbPath := newBaselineFixture(t)
+
+	handler, err := file.InitDB(dbPath, ctx)
+	if err != nil {
+		t.Fatalf("Erro ao migrar banco baseline - %v", err)
+	}
+	defer handler.DB.Close()
+
+	version, err := file.SchemaVersion(ctx, handler.DB)
+	if err != nil {
+		t.Fatalf("Erro ao ler versão do schema - %v", err)
+	}
+	if version != file.LatestSchemaVersion() {
+		t.Errorf("Versão esperada %d, obtida %d", file.LatestSchemaVersion(), version)
+	}
+
+	total, err := handler.GetTotalCount(ctx, file.NoteFilter{})
+	if err != nil {
+		t.Fatalf("Erro ao contar notas migradas - %v", err)
+	}
+	if total != 3 {
+		t.Errorf("Esperadas 3 notas após a migração, obtidas %d", total)
+	}
+
+	results, err := handler.FullSearchNote(ctx, "busca", file.NoteFilter{})
+	if err != nil {
+		t.Fatalf("Erro na busca após migração - %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("Esperado 1 resultado na busca após migração, obtidos %d", len(results))
+	}
+
+	// O índice refeito sem acentos deve conter as notas existentes.
+	results, err = handler.FullSearchNote(ctx, "anotacao", file.NoteFilter{})
+	if err != nil || len(results) != 1 || results[0].ID != 1 {
+		t.Errorf("Busca sem acento após migração: %v (%v)", results, err)
+	}
+
+	tags, err := handler.ListTags(ctx)
+	if err != nil {
+		t.Fatalf("Erro ao listar tags após migração - %v", err)
+	}
+	if len(tags) != 1 || tags[0].Name != "fixture" || tags[0].Count != 1 {
+		t.Errorf("Esperada a tag inline da nota existente, obtido %v", tags)
+	}
+
+	first, err := handler.GetNote(ctx, 1)
+	if err != nil || first.CreatedAt != 1700000000 || first.UpdatedAt != 1700000000 {
+		t.Errorf("A hora antiga deveria virar criação e edição: %+v (%v)", first, err)
+	}
+
+	inboxNotes, err := handler.GetTotalCount(ctx, file.NoteFilter{NotebookID: file.InboxNotebookID})
+	if err != nil || inboxNotes != 3 {
+		t.Errorf("Notas existentes deveriam ir para o Inbox: %d (%v)", inboxNotes, err)
+	}
+
+	// Reabrir um banco já migrado não deve reaplicar nada.
+	handler.DB.Close()
+	handler, err = file.InitDB(dbPath, ctx)
+	if err != nil {
+		t.Fatalf("Erro ao reabrir banco migrado - %v", err)
+	}
+	handler.DB.Close()
+}
+
+func TestInitDBRefusesNewerSchema(t *testing.T) {
+	ctx := context.Background()
+	dbPath := newBaselineFixture(t)
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("Erro ao abrir fixture - %v", err)
+	}
+	_, err = db.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, file.LatestSchemaVersion()+1))
+	db.Close()
+	if err != nil {
+		t.Fatalf("Erro ao alterar versão do fixture - %v", err)
+	}
+
+	_, err = file.InitDB(dbPath, ctx)
+	if !errors.Is(err, file.ErrSchemaTooNew) {
+		t.Errorf("Esperado ErrSchemaTooNew, obtido %v", err)
+	}
+}
+
+func TestMigrateRollsBackFailedMigration(t *testing.T) {
+	ctx := context.Background()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "rollback.db"))
+	if err != nil {
+		t.Fatalf("Erro ao abrir banco - %v", err)
+	}
+	defer db.Close()
+
+	list := []file.Migration{
+		{Version: 1, Name: "cria tabela a", Up: func(ctx context.Context, tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, `CREATE TABLE a (id INTEGER)`)
+			return err
+		}},
+		{Version: 2, Name: "falha no meio", Up: func(ctx context.Context, tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, `CREATE TABLE b (id INTEGER)`); err != nil {
+				return err
+			}
+			return errors.New("falha proposital")
+		}},
+	}
+
+	if err := file.Migrate(ctx, db, list); err == nil {
+		t.Fatal("Esperado erro na migração 2")
+	}
+
+	version, err := file.SchemaVersion(ctx, db)
+	if err != nil {
+		t.Fatalf("Erro ao ler versão do schema - %v", err)
+	}
+	if version != 1 {
+		t.Errorf("Versão esperada 1 após rollback, obtida %d", version)
+	}
+
+	var name string
+	err = db.QueryRow(`SELECT name FROM sqlite_master WHERE type='table' AND name='b'`).Scan(&name)
+	if err != sql.ErrNoRows {
+		t.Errorf("Tabela da migração com falha não deveria existir - %v", err)
+	}
+}
+
+func TestMigrateRejectsOutOfOrderList(t *testing.T) {
+	ctx := context.Background()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("Erro ao abrir banco - %v", err)
+	}
+	defer db.Close()
+
+	noop := func(ctx context.Context, tx *sql.Tx) error { return nil }
+	list := []file.Migration{
+		{Version: 2, Name: "fora de ordem", Up: noop},
+		{Version: 1, Name: "primeira", Up: noop},
+	}
+	if err := file.Migrate(ctx, db, list); err == nil {
+		t.Error("Esperado erro para lista de migrações fora de ordem")
+	}
+}
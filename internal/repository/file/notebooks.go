@@ -0,0 +1,164 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// InboxNotebookID é o caderno padrão, criado pela migração e que não pode ser
+// removido. Notas sem caderno definido vão para ele.
+const InboxNotebookID = 1
+
+var (
+	// ErrNotebookName indica um nome de caderno vazio ou com "/".
+	ErrNotebookName = errors.New("nome de caderno inválido")
+	// ErrNotebookDepth indica a tentativa de criar um caderno dentro de um subcaderno.
+	ErrNotebookDepth = errors.New("cadernos só podem ter um nível de subcadernos")
+	// ErrInboxNotebook indica a tentativa de remover o Inbox.
+	ErrInboxNotebook = errors.New("o caderno Inbox não pode ser removido")
+	// ErrNotebookHasChildren indica a remoção de um caderno que ainda tem subcadernos.
+	ErrNotebookHasChildren = errors.New("caderno possui subcadernos")
+)
+
+// Notebook é um caderno de notas. ParentID é 0 para cadernos de primeiro nível.
+type Notebook struct {
+	ID       int
+	Name     string
+	ParentID int
+}
+
+func normalizeNotebookName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.Contains(name, "/") {
+		return "", fmt.Errorf("%w: %q", ErrNotebookName, name)
+	}
+	return name, nil
+}
+
+// CreateNotebook cria um caderno dentro de parentID, ou no primeiro nível se
+// parentID for 0. Nomes se repetem apenas em pais diferentes.
+func (s SqliteHandler) CreateNotebook(ctx context.Context, name string, parentID int) (int64, error) {
+	name, err := normalizeNotebookName(name)
+	if err != nil {
+		return 0, err
+	}
+	if parentID != 0 {
+		var grandParent int
+		err := s.DB.QueryRowContext(ctx, `SELECT parent_id FROM notebooks WHERE id = ?`, parentID).Scan(&grandParent)
+		if err != nil {
+			return 0, fmt.Errorf("caderno pai %d: %w", parentID, err)
+		}
+		if grandParent != 0 {
+			return 0, ErrNotebookDepth
+		}
+	}
+
+	res, err := s.DB.ExecContext(ctx, `INSERT INTO notebooks (name, parent_id) VALUES (?, ?)`, name, parentID)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao criar caderno %q: %v", name, err)
+	}
+	return res.LastInsertId()
+}
+
+// ListNotebooks retorna os cadernos em ordem de exibição: cada caderno de
+// primeiro nível seguido dos seus subcadernos, ambos em ordem alfabética, com
+// o Inbox sempre primeiro.
+func (s SqliteHandler) ListNotebooks(ctx context.Context) ([]Notebook, error) {
+	rows, err := s.DB.QueryContext(ctx, `SELECT id, name, parent_id FROM notebooks`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	children := map[int][]Notebook{}
+	for rows.Next() {
+		var nb Notebook
+		if err := rows.Scan(&nb.ID, &nb.Name, &nb.ParentID); err != nil {
+			return nil, err
+		}
+		children[nb.ParentID] = append(children[nb.ParentID], nb)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, list := range children {
+		sort.Slice(list, func(i, j int) bool {
+			if (list[i].ID == InboxNotebookID) != (list[j].ID == InboxNotebookID) {
+				return list[i].ID == InboxNotebookID
+			}
+			return strings.ToLower(list[i].Name) < strings.ToLower(list[j].Name)
+		})
+	}
+
+	var notebooks []Notebook
+	for _, parent := range children[0] {
+		notebooks = append(notebooks, parent)
+		notebooks = append(notebooks, children[parent.ID]...)
+	}
+	return notebooks, nil
+}
+
+// RenameNotebook troca o nome do caderno, mantendo a posição na hierarquia.
+func (s SqliteHandler) RenameNotebook(ctx context.Context, notebookID int, name string) (int64, error) {
+	name, err := normalizeNotebookName(name)
+	if err != nil {
+		return 0, err
+	}
+	row, err := s.DB.ExecContext(ctx, `UPDATE notebooks SET name = ? WHERE id = ?`, name, notebookID)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao renomear caderno %d: %v", notebookID, err)
+	}
+	return row.RowsAffected()
+}
+
+// DeleteNotebook remove um caderno sem subcadernos; as notas dele vão para o Inbox.
+func (s SqliteHandler) DeleteNotebook(ctx context.Context, notebookID int) (int64, error) {
+	if notebookID == InboxNotebookID {
+		return 0, ErrInboxNotebook
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var children int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM notebooks WHERE parent_id = ?`, notebookID).Scan(&children); err != nil {
+		return 0, err
+	}
+	if children > 0 {
+		return 0, ErrNotebookHasChildren
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE notas SET notebook_id = ? WHERE notebook_id = ?`, InboxNotebookID, notebookID); err != nil {
+		return 0, fmt.Errorf("erro ao mover notas do caderno %d: %v", notebookID, err)
+	}
+	row, err := tx.ExecContext(ctx, `DELETE FROM notebooks WHERE id = ?`, notebookID)
+	if err != nil {
+		return 0, err
+	}
+	ra, err := row.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return ra, tx.Commit()
+}
+
+// MoveNote move a nota para o caderno. Retorna sql.ErrNoRows se o caderno não
+// existir; notas na lixeira não são movidas.
+func (s SqliteHandler) MoveNote(ctx context.Context, noteId int, notebookID int) (int64, error) {
+	var exists int
+	if err := s.DB.QueryRowContext(ctx, `SELECT 1 FROM notebooks WHERE id = ?`, notebookID).Scan(&exists); err != nil {
+		return 0, err
+	}
+	row, err := s.DB.ExecContext(ctx, `UPDATE notas SET notebook_id = ? WHERE id = ? AND deleted_at = 0`, notebookID, noteId)
+	if err != nil {
+		return 0, err
+	}
+	return row.RowsAffected()
+}
@@ -0,0 +1,133 @@
+package file_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/gustavo-silva98/adnotes/internal/repository/file"
+)
+
+func TestCreateAndListNotebooks(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+
+	work, err := handler.CreateNotebook(ctx, "Trabalho", 0)
+	if err != nil {
+		t.Fatalf("Erro ao criar caderno: %v", err)
+	}
+	if _, err := handler.CreateNotebook(ctx, "Projeto X", int(work)); err != nil {
+		t.Fatalf("Erro ao criar subcaderno: %v", err)
+	}
+	if _, err := handler.CreateNotebook(ctx, "Casa", 0); err != nil {
+		t.Fatalf("Erro ao criar caderno: %v", err)
+	}
+
+	notebooks, err := handler.ListNotebooks(ctx)
+	if err != nil {
+		t.Fatalf("Erro ao listar cadernos: %v", err)
+	}
+	var names []string
+	for _, nb := range notebooks {
+		names = append(names, nb.Name)
+	}
+	want := []string{"Inbox", "Casa", "Trabalho", "Projeto X"}
+	if len(names) != len(want) {
+		t.Fatalf("Cadernos listados %v, esperado %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("Cadernos listados %v, esperado %v", names, want)
+			break
+		}
+	}
+	if notebooks[3].ParentID != int(work) {
+		t.Errorf("Subcaderno com pai %d, esperado %d", notebooks[3].ParentID, work)
+	}
+}
+
+func TestCreateNotebookValidation(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+
+	parent, _ := handler.CreateNotebook(ctx, "Pai", 0)
+	child, _ := handler.CreateNotebook(ctx, "Filho", int(parent))
+
+	if _, err := handler.CreateNotebook(ctx, "Neto", int(child)); !errors.Is(err, file.ErrNotebookDepth) {
+		t.Errorf("Esperado ErrNotebookDepth, obtido %v", err)
+	}
+	if _, err := handler.CreateNotebook(ctx, "a/b", 0); !errors.Is(err, file.ErrNotebookName) {
+		t.Errorf("Esperado ErrNotebookName, obtido %v", err)
+	}
+	if _, err := handler.CreateNotebook(ctx, "Pai", 0); err == nil {
+		t.Error("Esperado erro ao repetir nome no mesmo nível")
+	}
+	if _, err := handler.CreateNotebook(ctx, "Pai", int(parent)); err != nil {
+		t.Errorf("Mesmo nome em outro pai deveria ser aceito: %v", err)
+	}
+}
+
+func TestMoveNoteScopesQueries(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+
+	notebook, _ := handler.CreateNotebook(ctx, "Trabalho", 0)
+	moved, _ := handler.InsertNote(&file.Note{CreatedAt: 1, NoteText: "relatório"}, ctx)
+	kept, _ := handler.InsertNote(&file.Note{CreatedAt: 1, NoteText: "relatório pessoal"}, ctx)
+
+	if note, _ := handler.GetNote(ctx, int(kept)); note.NotebookID != file.InboxNotebookID {
+		t.Errorf("Nota nova deveria ir para o Inbox, foi para %d", note.NotebookID)
+	}
+	if _, err := handler.MoveNote(ctx, int(moved), int(notebook)); err != nil {
+		t.Fatalf("Erro ao mover nota: %v", err)
+	}
+	if _, err := handler.MoveNote(ctx, int(moved), 999); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Esperado sql.ErrNoRows para caderno inexistente, obtido %v", err)
+	}
+
+	filter := file.NoteFilter{NotebookID: int(notebook)}
+	notes, err := handler.QueryNotes(ctx, file.QueryOptions{NoteFilter: filter})
+	if err != nil {
+		t.Fatalf("Erro na consulta por caderno: %v", err)
+	}
+	if len(notes) != 1 || notes[0].ID != int(moved) {
+		t.Errorf("Consulta por caderno retornou %v", notes)
+	}
+	if total, _ := handler.GetTotalCount(ctx, filter); total != 1 {
+		t.Errorf("Contagem por caderno esperada 1, obtida %d", total)
+	}
+	if total, _ := handler.GetTotalCount(ctx, file.NoteFilter{}); total != 2 {
+		t.Errorf("Contagem sem caderno esperada 2, obtida %d", total)
+	}
+	results, _ := handler.FullSearchNote(ctx, "relat", filter)
+	if len(results) != 1 {
+		t.Errorf("Busca no caderno retornou %v", results)
+	}
+}
+
+func TestDeleteNotebook(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+
+	if _, err := handler.DeleteNotebook(ctx, file.InboxNotebookID); !errors.Is(err, file.ErrInboxNotebook) {
+		t.Errorf("Esperado ErrInboxNotebook, obtido %v", err)
+	}
+
+	parent, _ := handler.CreateNotebook(ctx, "Pai", 0)
+	child, _ := handler.CreateNotebook(ctx, "Filho", int(parent))
+	id, _ := handler.InsertNote(&file.Note{CreatedAt: 1, NoteText: "nota", NotebookID: int(child)}, ctx)
+
+	if _, err := handler.DeleteNotebook(ctx, int(parent)); !errors.Is(err, file.ErrNotebookHasChildren) {
+		t.Errorf("Esperado ErrNotebookHasChildren, obtido %v", err)
+	}
+	if removed, err := handler.DeleteNotebook(ctx, int(child)); err != nil || removed != 1 {
+		t.Fatalf("Erro ao remover caderno: %v (%d linhas)", err, removed)
+	}
+	if note, _ := handler.GetNote(ctx, int(id)); note.NotebookID != file.InboxNotebookID {
+		t.Errorf("Nota de caderno removido deveria ir para o Inbox, está em %d", note.NotebookID)
+	}
+	if renamed, err := handler.RenameNotebook(ctx, int(parent), "Novo"); err != nil || renamed != 1 {
+		t.Errorf("Erro ao renomear caderno: %v (%d linhas)", err, renamed)
+	}
+}
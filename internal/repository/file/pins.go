@@ -0,0 +1,22 @@
+package file
+
+import "context"
+
+// PinNote fixa a nota no topo da leitura. Retorna 0 se a nota não existir ou
+// estiver na lixeira.
+func (s SqliteHandler) PinNote(ctx context.Context, noteId int) (int64, error) {
+	return s.setPinned(ctx, noteId, true)
+}
+
+// UnpinNote desafixa a nota. Retorna 0 se a nota não existir ou estiver na lixeira.
+func (s SqliteHandler) UnpinNote(ctx context.Context, noteId int) (int64, error) {
+	return s.setPinned(ctx, noteId, false)
+}
+
+func (s SqliteHandler) setPinned(ctx context.Context, noteId int, pinned bool) (int64, error) {
+	row, err := s.DB.ExecContext(ctx, `UPDATE notas SET pinned = ? WHERE id = ? AND deleted_at = 0`, pinned, noteId)
+	if err != nil {
+		return 0, err
+	}
+	return row.RowsAffected()
+}
@@ -0,0 +1,76 @@
+package file_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gustavo-silva98/adnotes/internal/repository/file"
+)
+
+func TestPinAndUnpinNote(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+
+	id, _ := handler.InsertNote(&file.Note{CreatedAt: 1, NoteText: "runbook de plantão"}, ctx)
+	if n, err := handler.PinNote(ctx, int(id)); err != nil || n != 1 {
+		t.Fatalf("PinNote = %d, %v", n, err)
+	}
+	if note, _ := handler.GetNote(ctx, int(id)); !note.Pinned {
+		t.Errorf("Nota deveria estar fixada: %+v", note)
+	}
+	if n, err := handler.UnpinNote(ctx, int(id)); err != nil || n != 1 {
+		t.Fatalf("UnpinNote = %d, %v", n, err)
+	}
+	if note, _ := handler.GetNote(ctx, int(id)); note.Pinned {
+		t.Errorf("Nota não deveria estar fixada: %+v", note)
+	}
+
+	if n, _ := handler.PinNote(ctx, 999); n != 0 {
+		t.Errorf("Nota inexistente não deveria ser fixada")
+	}
+	handler.DeleteNoteRepository(ctx, int(id))
+	if n, _ := handler.PinNote(ctx, int(id)); n != 0 {
+		t.Errorf("Nota na lixeira não deveria ser fixada")
+	}
+}
+
+func TestPinnedFirstAcrossPages(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+
+	var ids []int
+	for i := 1; i <= 5; i++ {
+		id, _ := handler.InsertNote(&file.Note{CreatedAt: int64(i), NoteText: "nota"}, ctx)
+		ids = append(ids, int(id))
+	}
+	// As duas mais antigas ficariam na última página sem a fixação.
+	handler.PinNote(ctx, ids[0])
+	handler.PinNote(ctx, ids[1])
+
+	opts := file.QueryOptions{PinnedFirst: true, Limit: 3}
+	first, err := handler.QueryNotes(ctx, opts)
+	if err != nil {
+		t.Fatalf("Erro ao consultar notas - %v", err)
+	}
+	if got := noteIDs(first); len(got) != 3 || got[0] != ids[1] || got[1] != ids[0] || got[2] != ids[4] {
+		t.Errorf("Primeira página deveria começar pelas fixadas: %v", got)
+	}
+
+	opts.Offset = 3
+	second, _ := handler.QueryNotes(ctx, opts)
+	if got := noteIDs(second); len(got) != 2 || got[0] != ids[3] || got[1] != ids[2] {
+		t.Errorf("Segunda página não deveria repetir as fixadas: %v", got)
+	}
+
+	// Em ordem crescente as fixadas continuam no topo.
+	opts = file.QueryOptions{PinnedFirst: true, Direction: file.SortAsc, Limit: 3}
+	asc, _ := handler.QueryNotes(ctx, opts)
+	if got := noteIDs(asc); got[0] != ids[0] || got[1] != ids[1] || got[2] != ids[2] {
+		t.Errorf("Ordem crescente deveria manter as fixadas no topo: %v", got)
+	}
+
+	plain, _ := handler.QueryNotes(ctx, file.QueryOptions{Limit: 1})
+	if plain[0].ID != ids[4] {
+		t.Errorf("Sem PinnedFirst a ordem ignora a fixação: %v", noteIDs(plain))
+	}
+}
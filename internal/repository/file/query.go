@@ -0,0 +1,128 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrSortField indica um nome de ordenação desconhecido.
+var ErrSortField = errors.New("ordenação inválida")
+
+// SortField é o critério de ordenação de QueryNotes.
+type SortField int
+
+const (
+	// SortCreated ordena pela data de criação.
+	SortCreated SortField = iota
+	// SortUpdated ordena pela data da última edição.
+	SortUpdated
+	// SortText ordena alfabeticamente pelo texto, sem diferenciar maiúsculas.
+	SortText
+)
+
+// SortFields lista os critérios na ordem em que a leitura os alterna.
+var SortFields = []SortField{SortCreated, SortUpdated, SortText}
+
+func (f SortField) String() string {
+	switch f {
+	case SortUpdated:
+		return "editadas"
+	case SortText:
+		return "texto"
+	default:
+		return "criadas"
+	}
+}
+
+// ParseSortField converte o nome usado na API e na linha de comando
+// ("created", "updated" ou "text") em um SortField.
+func ParseSortField(name string) (SortField, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "created":
+		return SortCreated, nil
+	case "updated":
+		return SortUpdated, nil
+	case "text":
+		return SortText, nil
+	}
+	return 0, fmt.Errorf("%w: %q (use created, updated ou text)", ErrSortField, name)
+}
+
+// SortDirection é o sentido da ordenação. O valor zero é decrescente, que
+// nas datas põe as notas mais recentes primeiro.
+type SortDirection int
+
+const (
+	SortDesc SortDirection = iota
+	SortAsc
+)
+
+// QueryOptions configura QueryNotes: o filtro, a ordenação e a página.
+// Limit 0 não limita o número de notas. Com PinnedFirst, as notas fixadas vêm
+// antes de todas as outras, e portanto no topo da primeira página.
+type QueryOptions struct {
+	NoteFilter
+	Sort        SortField
+	Direction   SortDirection
+	PinnedFirst bool
+	Limit       int
+	Offset      int
+}
+
+// orderBy devolve a cláusula ORDER BY das opções. O id desempata no mesmo
+// sentido, para que a paginação seja estável.
+func (o QueryOptions) orderBy() string {
+	column := `n.created_at`
+	switch o.Sort {
+	case SortUpdated:
+		column = `n.updated_at`
+	case SortText:
+		column = `n.note_text COLLATE NOCASE`
+	}
+	dir := ` DESC`
+	if o.Direction == SortAsc {
+		dir = ` ASC`
+	}
+	order := `ORDER BY `
+	if o.PinnedFirst {
+		order += `n.pinned DESC, `
+	}
+	return order + column + dir + `, n.id` + dir
+}
+
+// QueryNotes retorna as notas que passam no filtro, já ordenadas e paginadas.
+func (s SqliteHandler) QueryNotes(ctx context.Context, opts QueryOptions) ([]Note, error) {
+	conds, args, err := opts.conditions()
+	if err != nil {
+		return nil, err
+	}
+	limit := opts.Limit
+	if limit == 0 {
+		// LIMIT -1 no SQLite não limita.
+		limit = -1
+	}
+
+	rows, err := s.DB.QueryContext(
+		ctx,
+		`SELECT `+noteColumns+`
+			FROM notas n `+whereClause(conds)+`
+			`+opts.orderBy()+` LIMIT ? OFFSET ?`,
+		append(args, limit, opts.Offset)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notes []Note
+	for rows.Next() {
+		note, err := scanNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, note)
+	}
+	return notes, rows.Err()
+}
@@ -0,0 +1,96 @@
+package file_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/gustavo-silva98/adnotes/internal/repository/file"
+)
+
+// noteIDs extrai os ids na ordem retornada.
+func noteIDs(notes []file.Note) []int {
+	ids := make([]int, len(notes))
+	for i, note := range notes {
+		ids[i] = note.ID
+	}
+	return ids
+}
+
+func TestQueryNotesSort(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+
+	old, _ := handler.InsertNote(&file.Note{CreatedAt: 100, UpdatedAt: 300, NoteText: "beta, antiga editada"}, ctx)
+	recent, _ := handler.InsertNote(&file.Note{CreatedAt: 200, NoteText: "Alfa recente"}, ctx)
+	last, _ := handler.InsertNote(&file.Note{CreatedAt: 200, NoteText: "gama"}, ctx)
+
+	for _, tc := range []struct {
+		opts file.QueryOptions
+		want []int64
+	}{
+		{file.QueryOptions{}, []int64{last, recent, old}},
+		{file.QueryOptions{Direction: file.SortAsc}, []int64{old, recent, last}},
+		{file.QueryOptions{Sort: file.SortUpdated}, []int64{old, last, recent}},
+		{file.QueryOptions{Sort: file.SortText, Direction: file.SortAsc}, []int64{recent, old, last}},
+		{file.QueryOptions{Sort: file.SortText}, []int64{last, old, recent}},
+	} {
+		notes, err := handler.QueryNotes(ctx, tc.opts)
+		if err != nil {
+			t.Fatalf("Erro ao consultar notas - %v", err)
+		}
+		want := make([]int, len(tc.want))
+		for i, id := range tc.want {
+			want[i] = int(id)
+		}
+		if got := noteIDs(notes); !slices.Equal(got, want) {
+			t.Errorf("%+v: ordem %v, esperada %v", tc.opts, got, want)
+		}
+	}
+
+	page, err := handler.QueryNotes(ctx, file.QueryOptions{Limit: 1, Offset: 1})
+	if err != nil || len(page) != 1 || page[0].ID != int(recent) {
+		t.Errorf("Segunda página deveria trazer só a nota %d: %v (%v)", recent, noteIDs(page), err)
+	}
+}
+
+func TestQueryNotesDateRange(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+
+	for _, at := range []int64{100, 200, 300} {
+		handler.InsertNote(&file.Note{CreatedAt: at, NoteText: "nota"}, ctx)
+	}
+
+	for _, tc := range []struct {
+		filter file.NoteFilter
+		want   int
+	}{
+		{file.NoteFilter{From: 200}, 2},
+		{file.NoteFilter{To: 200}, 1},
+		{file.NoteFilter{From: 150, To: 300}, 1},
+	} {
+		notes, err := handler.QueryNotes(ctx, file.QueryOptions{NoteFilter: tc.filter})
+		if err != nil {
+			t.Fatalf("Erro ao consultar notas - %v", err)
+		}
+		if len(notes) != tc.want {
+			t.Errorf("%+v: %d notas, esperadas %d", tc.filter, len(notes), tc.want)
+		}
+		if total, _ := handler.GetTotalCount(ctx, tc.filter); total != tc.want {
+			t.Errorf("%+v: contagem %d, esperada %d", tc.filter, total, tc.want)
+		}
+	}
+}
+
+func TestParseSortField(t *testing.T) {
+	for name, want := range map[string]file.SortField{"": file.SortCreated, "Updated": file.SortUpdated, "text": file.SortText} {
+		if got, err := file.ParseSortField(name); err != nil || got != want {
+			t.Errorf("ParseSortField(%q) = %v, %v", name, got, err)
+		}
+	}
+	if _, err := file.ParseSortField("id"); !errors.Is(err, file.ErrSortField) {
+		t.Errorf("Esperado ErrSortField, obtido %v", err)
+	}
+}
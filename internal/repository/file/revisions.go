@@ -0,0 +1,68 @@
+package file
+
+import "context"
+
+// Revision é uma versão anterior do texto de uma nota. Hour é o horário em que
+// aquela versão havia sido salva.
+type Revision struct {
+	ID       int
+	NoteID   int
+	Hour     int64
+	NoteText string
+}
+
+// ListRevisions retorna as versões anteriores da nota, da mais nova para a mais antiga.
+func (s SqliteHandler) ListRevisions(ctx context.Context, noteId int) ([]Revision, error) {
+	rows, err := s.DB.QueryContext(
+		ctx,
+		`SELECT id, note_id, hour, note_text FROM note_revisions
+			WHERE note_id = ?
+			ORDER BY id DESC`,
+		noteId,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []Revision
+	for rows.Next() {
+		var r Revision
+		if err := rows.Scan(&r.ID, &r.NoteID, &r.Hour, &r.NoteText); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, r)
+	}
+	return revisions, rows.Err()
+}
+
+// GetRevision retorna uma versão pelo id, ou sql.ErrNoRows se ela não existir.
+func (s SqliteHandler) GetRevision(ctx context.Context, revisionID int) (Revision, error) {
+	var r Revision
+	err := s.DB.QueryRowContext(
+		ctx,
+		`SELECT id, note_id, hour, note_text FROM note_revisions WHERE id = ?`,
+		revisionID,
+	).Scan(&r.ID, &r.NoteID, &r.Hour, &r.NoteText)
+	return r, err
+}
+
+// RestoreRevision grava o texto da versão como uma nova edição da nota, com
+// horário hour. O texto atual vira mais uma versão, então a restauração também
+// pode ser desfeita pelo histórico.
+func (s SqliteHandler) RestoreRevision(ctx context.Context, revisionID int, hour int64) (int64, error) {
+	rev, err := s.GetRevision(ctx, revisionID)
+	if err != nil {
+		return 0, err
+	}
+	note, err := s.GetNote(ctx, rev.NoteID)
+	if err != nil {
+		return 0, err
+	}
+	if note.NoteText == rev.NoteText {
+		return 0, nil
+	}
+	note.NoteText = rev.NoteText
+	note.UpdatedAt = hour
+	return s.UpdateEditNoteRepository(ctx, note)
+}
@@ -0,0 +1,95 @@
+package file_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/gustavo-silva98/adnotes/internal/repository/file"
+)
+
+func TestUpdateRecordsRevision(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+
+	id, _ := handler.InsertNote(&file.Note{CreatedAt: 100, NoteText: "v1"}, ctx)
+	note, _ := handler.GetNote(ctx, int(id))
+
+	for i, text := range []string{"v2", "v2", "v3"} {
+		note.NoteText = text
+		note.UpdatedAt = int64(200 + i)
+		if _, err := handler.UpdateEditNoteRepository(ctx, note); err != nil {
+			t.Fatalf("Erro ao editar nota: %v", err)
+		}
+	}
+	// Mudanças fora do texto não geram versão.
+	if _, err := handler.SetReminder(ctx, int(id), 500, 0); err != nil {
+		t.Fatalf("Erro ao definir lembrete: %v", err)
+	}
+
+	revisions, err := handler.ListRevisions(ctx, int(id))
+	if err != nil {
+		t.Fatalf("Erro ao listar versões: %v", err)
+	}
+	if len(revisions) != 2 {
+		t.Fatalf("Esperadas 2 versões, obtidas %d: %+v", len(revisions), revisions)
+	}
+	if revisions[0].NoteText != "v2" || revisions[1].NoteText != "v1" || revisions[1].Hour != 100 {
+		t.Errorf("Versões inesperadas: %+v", revisions)
+	}
+
+	rev, err := handler.GetRevision(ctx, revisions[1].ID)
+	if err != nil || rev != revisions[1] {
+		t.Errorf("GetRevision = %+v, %v", rev, err)
+	}
+	if _, err := handler.GetRevision(ctx, 999); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Esperado sql.ErrNoRows, obtido %v", err)
+	}
+}
+
+func TestRestoreRevision(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+
+	id, _ := handler.InsertNote(&file.Note{CreatedAt: 100, NoteText: "original #velha"}, ctx)
+	note, _ := handler.GetNote(ctx, int(id))
+	note.NoteText = "editada por engano"
+	note.UpdatedAt = 200
+	handler.UpdateEditNoteRepository(ctx, note)
+
+	revisions, _ := handler.ListRevisions(ctx, int(id))
+	if n, err := handler.RestoreRevision(ctx, revisions[0].ID, 300); err != nil || n != 1 {
+		t.Fatalf("RestoreRevision = %d, %v", n, err)
+	}
+
+	restored, _ := handler.GetNote(ctx, int(id))
+	if restored.NoteText != "original #velha" || restored.UpdatedAt != 300 {
+		t.Errorf("Nota restaurada inesperada: %+v", restored)
+	}
+	if len(restored.Tags) != 1 || restored.Tags[0] != "velha" {
+		t.Errorf("Tags do texto restaurado não sincronizadas: %v", restored.Tags)
+	}
+	revisions, _ = handler.ListRevisions(ctx, int(id))
+	if len(revisions) != 2 || revisions[0].NoteText != "editada por engano" {
+		t.Errorf("Restauração deveria guardar o texto substituído: %+v", revisions)
+	}
+}
+
+func TestPurgeNoteRemovesRevisions(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+
+	id, _ := handler.InsertNote(&file.Note{CreatedAt: 100, NoteText: "a"}, ctx)
+	note, _ := handler.GetNote(ctx, int(id))
+	note.NoteText = "b"
+	handler.UpdateEditNoteRepository(ctx, note)
+	handler.DeleteNoteRepository(ctx, int(id))
+	handler.PurgeNote(ctx, int(id))
+
+	var count int
+	handler.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM note_revisions`).Scan(&count)
+	if count != 0 {
+		t.Errorf("Versões de nota removida continuam no banco: %d", count)
+	}
+}
@@ -0,0 +1,69 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrSavedSearch indica uma busca salva sem nome ou sem consulta.
+var ErrSavedSearch = errors.New("busca salva precisa de nome e consulta")
+
+// SavedSearch é uma busca guardada com um nome. Na leitura, ela aparece como
+// um caderno virtual: as notas de NoteFilter{Search: Query}.
+type SavedSearch struct {
+	ID    int
+	Name  string
+	Query string
+}
+
+// SaveSearch guarda query com o nome informado e retorna o id. Se já houver
+// uma busca com esse nome, sem diferenciar maiúsculas, a consulta dela é
+// substituída.
+func (s SqliteHandler) SaveSearch(ctx context.Context, name string, query string) (int64, error) {
+	name, query = strings.TrimSpace(name), strings.TrimSpace(query)
+	if name == "" || query == "" {
+		return 0, ErrSavedSearch
+	}
+	var id int64
+	err := s.DB.QueryRowContext(
+		ctx,
+		`INSERT INTO saved_searches (name, query) VALUES (?, ?)
+			ON CONFLICT (name) DO UPDATE SET query = excluded.query
+			RETURNING id`,
+		name, query,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao salvar busca %q: %v", name, err)
+	}
+	return id, nil
+}
+
+// ListSavedSearches retorna as buscas salvas em ordem alfabética.
+func (s SqliteHandler) ListSavedSearches(ctx context.Context) ([]SavedSearch, error) {
+	rows, err := s.DB.QueryContext(ctx, `SELECT id, name, query FROM saved_searches ORDER BY name COLLATE NOCASE`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var searches []SavedSearch
+	for rows.Next() {
+		var search SavedSearch
+		if err := rows.Scan(&search.ID, &search.Name, &search.Query); err != nil {
+			return nil, err
+		}
+		searches = append(searches, search)
+	}
+	return searches, rows.Err()
+}
+
+// DeleteSavedSearch remove a busca salva. As notas não são afetadas.
+func (s SqliteHandler) DeleteSavedSearch(ctx context.Context, searchID int) (int64, error) {
+	res, err := s.DB.ExecContext(ctx, `DELETE FROM saved_searches WHERE id = ?`, searchID)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,74 @@
+package file_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/gustavo-silva98/adnotes/internal/repository/file"
+)
+
+func TestSavedSearches(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+
+	id, err := handler.SaveSearch(ctx, " Plantão ", "tag:oncall after:last-week")
+	if err != nil {
+		t.Fatalf("Erro ao salvar busca: %v", err)
+	}
+	if _, err := handler.SaveSearch(ctx, "deploys", "deploy -staging"); err != nil {
+		t.Fatalf("Erro ao salvar busca: %v", err)
+	}
+	again, err := handler.SaveSearch(ctx, "plantão", "tag:oncall")
+	if err != nil || again != id {
+		t.Errorf("Salvar com o mesmo nome deveria atualizar a busca %d: %d (%v)", id, again, err)
+	}
+	for _, invalid := range [][2]string{{"", "deploy"}, {"vazia", "  "}} {
+		if _, err := handler.SaveSearch(ctx, invalid[0], invalid[1]); !errors.Is(err, file.ErrSavedSearch) {
+			t.Errorf("SaveSearch(%q, %q) = %v, esperado ErrSavedSearch", invalid[0], invalid[1], err)
+		}
+	}
+
+	searches, err := handler.ListSavedSearches(ctx)
+	want := []file.SavedSearch{{ID: 2, Name: "deploys", Query: "deploy -staging"}, {ID: int(id), Name: "Plantão", Query: "tag:oncall"}}
+	if err != nil || !slices.Equal(searches, want) {
+		t.Errorf("ListSavedSearches = %+v (%v), esperado %+v", searches, err, want)
+	}
+
+	if n, err := handler.DeleteSavedSearch(ctx, int(id)); err != nil || n != 1 {
+		t.Errorf("DeleteSavedSearch = %d, %v", n, err)
+	}
+	if searches, _ := handler.ListSavedSearches(ctx); len(searches) != 1 {
+		t.Errorf("Busca removida continua listada: %+v", searches)
+	}
+}
+
+func TestQueryNotesBySearch(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+
+	var deploys []int
+	for i := range 5 {
+		id, _ := handler.InsertNote(&file.Note{CreatedAt: int64(100 + i), NoteText: "deploy #oncall"}, ctx)
+		deploys = append(deploys, int(id))
+	}
+	handler.InsertNote(&file.Note{CreatedAt: 200, NoteText: "deploy em staging #oncall"}, ctx)
+	handler.InsertNote(&file.Note{CreatedAt: 300, NoteText: "deploy sem tag"}, ctx)
+
+	filter := file.NoteFilter{Search: "deploy tag:oncall -staging"}
+	if total, err := handler.GetTotalCount(ctx, filter); err != nil || total != 5 {
+		t.Errorf("GetTotalCount = %d (%v), esperado 5", total, err)
+	}
+	first, _ := handler.QueryNotes(ctx, file.QueryOptions{NoteFilter: filter, Direction: file.SortAsc, Limit: 3})
+	second, _ := handler.QueryNotes(ctx, file.QueryOptions{NoteFilter: filter, Direction: file.SortAsc, Limit: 3, Offset: 3})
+	if got := append(noteIDs(first), noteIDs(second)...); !slices.Equal(got, deploys) {
+		t.Errorf("Páginas da busca = %v, esperado %v", got, deploys)
+	}
+
+	// A busca salva combina com os outros filtros.
+	filter.To = 102
+	if total, _ := handler.GetTotalCount(ctx, filter); total != 2 {
+		t.Errorf("Busca com período: %d notas, esperado 2", total)
+	}
+}
@@ -0,0 +1,104 @@
+package file
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SnippetStart e SnippetEnd delimitam, em SearchResult.Snippet, os termos
+// encontrados. São caracteres de controle, que não aparecem no texto das
+// notas, para que cada interface escolha como destacá-los.
+const (
+	SnippetStart = "\x02"
+	SnippetEnd   = "\x03"
+)
+
+// SearchResult é uma nota encontrada por FullSearchNote. Snippet é o trecho
+// do texto, de até 12 palavras, em volta dos termos buscados, com eles entre
+// SnippetStart e SnippetEnd. Fica vazio quando a busca não tem palavras.
+// Matches só vem de FuzzySearchNote: são as palavras da nota, em minúsculas e
+// sem acentos, que casaram com as buscadas, para o realce.
+type SearchResult struct {
+	Note
+	Snippet string
+	Matches []string
+}
+
+// FullSearchNote busca argQuery, interpretada por ParseSearchQuery, e devolve
+// as notas da mais para a menos relevante, segundo o bm25. Uma busca inválida
+// vira busca literal; quem quiser mostrar o erro chama ParseSearchQuery. Os
+// qualificadores restringem o resultado assim como filter. Uma busca sem
+// palavras a procurar não tem relevância e vem das notas mais novas para as
+// mais antigas.
+func (s SqliteHandler) FullSearchNote(ctx context.Context, argQuery string, filter NoteFilter) ([]SearchResult, error) {
+	q, _ := ParseSearchQuery(argQuery, time.Now())
+	if q.Empty() && filter.Tag == "" {
+		// Sem termos nem tags não há o que buscar.
+		return nil, nil
+	}
+	conds, args, err := q.conditions(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var query string
+	if q.Match != "" {
+		conds = append([]string{`notes_fts MATCH ?`}, conds...)
+		args = append([]any{q.Match}, args...)
+		query = `SELECT ` + noteColumns + `,
+				snippet(notes_fts, 0, '` + SnippetStart + `', '` + SnippetEnd + `', '…', 12)
+			FROM notes_fts
+			INNER JOIN notas n ON n.id = notes_fts.rowid
+			` + whereClause(conds) + `
+			ORDER BY bm25(notes_fts), n.id DESC`
+	} else {
+		query = `SELECT ` + noteColumns + `, ''
+			FROM notas n
+			` + whereClause(conds) + `
+			ORDER BY n.created_at DESC, n.id DESC`
+	}
+
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []SearchResult
+	for rows.Next() {
+		var result SearchResult
+		result.Note, err = scanNote(rows, &result.Snippet)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// conditions junta os qualificadores e as exclusões da busca às condições de
+// filter, sem o MATCH dos termos.
+func (q SearchQuery) conditions(filter NoteFilter, extraTags ...string) ([]string, []any, error) {
+	if q.After > filter.From {
+		filter.From = q.After
+	}
+	if q.Before != 0 && (filter.To == 0 || q.Before < filter.To) {
+		filter.To = q.Before
+	}
+	conds, args, err := filter.conditions(slices.Concat(q.Tags, extraTags)...)
+	if err != nil {
+		return nil, nil, err
+	}
+	if q.Exclude != "" {
+		conds = append(conds, `n.id NOT IN (SELECT rowid FROM notes_fts WHERE notes_fts MATCH ?)`)
+		args = append(args, q.Exclude)
+	}
+	return conds, args, nil
+}
+
+// StripSnippet remove os marcadores de um Snippet, para saídas sem destaque.
+func StripSnippet(snippet string) string {
+	return strings.NewReplacer(SnippetStart, "", SnippetEnd, "").Replace(snippet)
+}
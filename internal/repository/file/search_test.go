@@ -0,0 +1,176 @@
+package file_test
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gustavo-silva98/adnotes/internal/repository/file"
+)
+
+func TestFullSearchNoteRanking(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+
+	once, _ := handler.InsertNote(&file.Note{CreatedAt: 300, NoteText: "Ata da reunião de segunda, com um relatório anexo e outros assuntos da semana"}, ctx)
+	often, _ := handler.InsertNote(&file.Note{CreatedAt: 100, NoteText: "Relatório mensal: o relatório fecha o relatório anterior"}, ctx)
+	handler.InsertNote(&file.Note{CreatedAt: 200, NoteText: "Lista de compras"}, ctx)
+
+	results, err := handler.FullSearchNote(ctx, "relatório", file.NoteFilter{})
+	if err != nil {
+		t.Fatalf("Erro na busca: %v", err)
+	}
+	ids := make([]int, len(results))
+	for i, r := range results {
+		ids[i] = r.ID
+	}
+	if !slices.Equal(ids, []int{int(often), int(once)}) {
+		t.Fatalf("Ordem por relevância = %v, esperado [%d %d]", ids, often, once)
+	}
+
+	snippet := results[1].Snippet
+	if !strings.Contains(snippet, file.SnippetStart+"relatório"+file.SnippetEnd) {
+		t.Errorf("Trecho sem o termo marcado: %q", snippet)
+	}
+	if !strings.Contains(snippet, "…") {
+		t.Errorf("Trecho de texto longo deveria vir cortado: %q", snippet)
+	}
+	if got := file.StripSnippet(snippet); strings.ContainsAny(got, file.SnippetStart+file.SnippetEnd) {
+		t.Errorf("StripSnippet manteve marcadores: %q", got)
+	}
+
+	handler.AddTag(ctx, int(once), "ata")
+	results, _ = handler.FullSearchNote(ctx, "tag:ata", file.NoteFilter{})
+	if len(results) != 1 || results[0].Snippet != "" {
+		t.Errorf("Busca só por tag = %+v", results)
+	}
+}
+
+func TestParseSearchQuery(t *testing.T) {
+	now := time.Date(2025, 3, 15, 18, 30, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		in   string
+		want file.SearchQuery
+	}{
+		{"relat", file.SearchQuery{Match: `"relat"*`, Terms: []string{"relat"}}},
+		{`ata "plano de ação" AND pauta`, file.SearchQuery{Match: `"ata" "plano de ação" "pauta"*`, Terms: []string{"ata", "plano", "de", "ação", "pauta"}}},
+		{"deploy OR rollback -staging NOT teste", file.SearchQuery{Match: `"deploy" OR "rollback"*`, Exclude: `"staging" OR "teste"`, Terms: []string{"deploy", "rollback"}}},
+		{`kube* "nó*" -"dev"*`, file.SearchQuery{Match: `"kube"* "nó*"`, Exclude: `"dev"*`, Terms: []string{"kube", "nó"}}},
+		{"tag:#OnCall after:last-week before:2025-03-20", file.SearchQuery{
+			Tags:   []string{"oncall"},
+			After:  time.Date(2025, 3, 8, 0, 0, 0, 0, time.UTC).Unix(),
+			Before: time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC).Unix(),
+		}},
+		{"c++ - !", file.SearchQuery{Match: `"c++"*`, Terms: []string{"c"}}},
+	} {
+		got, err := file.ParseSearchQuery(tc.in, now)
+		if err != nil {
+			t.Errorf("%q: erro inesperado %v", tc.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%q = %+v, esperado %+v", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestParseSearchQueryErrors(t *testing.T) {
+	now := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+	for _, in := range []string{
+		`"sem fim`,
+		"OR reunião",
+		"ata OR",
+		"ata OR OR pauta",
+		"ata OR -pauta",
+		"ata NOT",
+		"after:ontem",
+		"tag:",
+		"-tag:casa",
+		"after:2025-03-10 before:2025-03-01",
+	} {
+		q, err := file.ParseSearchQuery(in, now)
+		if err == nil {
+			t.Errorf("%q deveria ser inválida", in)
+			continue
+		}
+		if q.Exclude != "" || len(q.Tags) != 0 || q.After != 0 || q.Before != 0 {
+			t.Errorf("%q: busca inválida deveria ser literal, obtido %+v", in, q)
+		}
+	}
+}
+
+func TestFullSearchNoteSyntax(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+
+	staging, _ := handler.InsertNote(&file.Note{CreatedAt: 100, NoteText: "deploy em staging"}, ctx)
+	prod, _ := handler.InsertNote(&file.Note{CreatedAt: 200, NoteText: "deploy em produção"}, ctx)
+	rollback, _ := handler.InsertNote(&file.Note{CreatedAt: 300, NoteText: "rollback do produto"}, ctx)
+
+	for _, tc := range []struct {
+		query string
+		want  []int64
+	}{
+		{"deploy -staging", []int64{prod}},
+		{`"em produção"`, []int64{prod}},
+		{`"produção em"`, nil},
+		{"staging OR rollback", []int64{rollback, staging}},
+		{"produ", []int64{rollback, prod}},
+		{"produ deploy", nil},
+		{"-rollback", []int64{prod, staging}},
+		{"deploy after:2000-01-01", nil},
+		// Entradas que quebrariam o MATCH viram busca literal.
+		{`deploy "staging`, []int64{staging}},
+		{"AND OR NOT", nil},
+		{`em)"(`, []int64{prod, staging}},
+	} {
+		results, err := handler.FullSearchNote(ctx, tc.query, file.NoteFilter{})
+		if err != nil {
+			t.Errorf("%q: erro %v", tc.query, err)
+			continue
+		}
+		got := make([]int64, len(results))
+		for i, r := range results {
+			got[i] = int64(r.ID)
+		}
+		slices.Sort(got)
+		want := slices.Clone(tc.want)
+		slices.Sort(want)
+		if !slices.Equal(got, want) {
+			t.Errorf("%q = %v, esperado %v", tc.query, got, want)
+		}
+	}
+}
+
+func TestFullSearchNoteIgnoresAccents(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+
+	note, _ := handler.InsertNote(&file.Note{CreatedAt: 100, NoteText: "Anotação da reunião sobre a manutenção às três"}, ctx)
+	plain, _ := handler.InsertNote(&file.Note{CreatedAt: 200, NoteText: "Relatorio sem acento, pra variar"}, ctx)
+
+	for _, tc := range []struct {
+		query string
+		want  int64
+	}{
+		{"anotacao", note},
+		{"ANOTAÇÃO", note},
+		{"manutenc", note},
+		{`"reuniao sobre a manutencao"`, note},
+		{`"as tres"`, note},
+		{"relatório", plain},
+	} {
+		results, err := handler.FullSearchNote(ctx, tc.query, file.NoteFilter{})
+		if err != nil || len(results) != 1 || results[0].ID != int(tc.want) {
+			t.Errorf("%q = %+v (%v), esperada a nota %d", tc.query, results, err, tc.want)
+		}
+	}
+
+	results, _ := handler.FullSearchNote(ctx, "anotacao", file.NoteFilter{})
+	if len(results) == 1 && !strings.Contains(results[0].Snippet, file.SnippetStart+"Anotação"+file.SnippetEnd) {
+		t.Errorf("Trecho deveria marcar a palavra acentuada: %q", results[0].Snippet)
+	}
+}
@@ -0,0 +1,274 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// SearchQuery é uma busca interpretada por ParseSearchQuery, pronta para o
+// FTS5. Match é a expressão das notas procuradas e Exclude a das notas
+// removidas do resultado; qualquer uma pode vir vazia. Terms são as palavras
+// procuradas, para realce. After e Before limitam a data de criação ao
+// intervalo [After, Before), em unix; 0 não limita.
+type SearchQuery struct {
+	Match   string
+	Exclude string
+	Terms   []string
+	Tags    []string
+	After   int64
+	Before  int64
+}
+
+// Empty informa se a busca não tem termos nem qualificadores.
+func (q SearchQuery) Empty() bool {
+	return q.Match == "" && q.Exclude == "" && len(q.Tags) == 0 && q.After == 0 && q.Before == 0
+}
+
+// searchDateLayouts são os formatos aceitos em after: e before:.
+var searchDateLayouts = []string{"2006-01-02", "02/01/2006"}
+
+// ParseSearchQuery interpreta a busca digitada:
+//
+//   - palavras soltas precisam aparecer todas; "palavra*" busca por prefixo, e
+//     a última palavra, ainda sendo digitada, também;
+//   - "uma frase" entre aspas busca as palavras em sequência;
+//   - -palavra ou NOT palavra remove as notas que a contêm;
+//   - OR entre dois termos aceita qualquer um deles, com precedência menor que
+//     a das palavras justapostas, como no FTS5;
+//   - tag:nome, after:data e before:data filtram por tag e pela data de
+//     criação; datas são AAAA-MM-DD, DD/MM/AAAA, today, yesterday, last-week
+//     ou last-month, no fuso de now, e before: não inclui o dia informado.
+//
+// Todo termo vai para o FTS5 entre aspas, então nenhuma entrada gera erro de
+// sintaxe no MATCH. Se s for inválida, o erro descreve o problema e a busca
+// devolvida procura as palavras de s literalmente.
+func ParseSearchQuery(s string, now time.Time) (SearchQuery, error) {
+	q, err := parseSearchQuery(s, now)
+	if err != nil {
+		return literalSearchQuery(s), err
+	}
+	return q, nil
+}
+
+// searchToken é um termo da busca: uma palavra ou uma frase entre aspas.
+type searchToken struct {
+	text   string
+	phrase bool
+	prefix bool
+	neg    bool
+}
+
+func parseSearchQuery(s string, now time.Time) (SearchQuery, error) {
+	tokens, err := lexSearch(s)
+	if err != nil {
+		return SearchQuery{}, err
+	}
+
+	var q SearchQuery
+	var match, exclude []string
+	lastWord := -1
+	negNext := false
+	for _, tok := range tokens {
+		if !tok.phrase && !tok.neg {
+			switch tok.text {
+			case "AND":
+				continue
+			case "OR":
+				if negNext {
+					return SearchQuery{}, errors.New("NOT precisa de um termo")
+				}
+				match = append(match, "OR")
+				continue
+			case "NOT":
+				negNext = true
+				continue
+			}
+		}
+		if negNext {
+			tok.neg = true
+			negNext = false
+		}
+
+		if !tok.phrase {
+			if name, value, ok := strings.Cut(tok.text, ":"); ok {
+				qualifier := strings.ToLower(name)
+				if qualifier == "tag" || qualifier == "after" || qualifier == "before" {
+					if tok.neg {
+						return SearchQuery{}, fmt.Errorf("%s: não aceita exclusão", qualifier)
+					}
+					if err := q.qualify(qualifier, value, now); err != nil {
+						return SearchQuery{}, err
+					}
+					continue
+				}
+			}
+		}
+
+		term := ftsTerm(tok)
+		if term == "" {
+			// Só pontuação: o tokenizador do FTS ignoraria o termo.
+			continue
+		}
+		if tok.neg {
+			exclude = append(exclude, term)
+			continue
+		}
+		if !tok.phrase && !tok.prefix {
+			lastWord = len(match)
+		}
+		match = append(match, term)
+		q.Terms = append(q.Terms, termWords(tok.text)...)
+	}
+	if negNext {
+		return SearchQuery{}, errors.New("NOT precisa de um termo")
+	}
+	for i, term := range match {
+		if term == "OR" && (i == 0 || i == len(match)-1 || match[i-1] == "OR" || match[i+1] == "OR") {
+			return SearchQuery{}, errors.New("OR precisa de um termo de cada lado")
+		}
+	}
+	if q.After != 0 && q.Before != 0 && q.After >= q.Before {
+		return SearchQuery{}, errors.New("after: deve ser anterior a before:")
+	}
+
+	if lastWord >= 0 {
+		match[lastWord] += "*"
+	}
+	q.Match = strings.Join(match, " ")
+	q.Exclude = strings.Join(exclude, " OR ")
+	return q, nil
+}
+
+// qualify aplica um qualificador tag:, after: ou before: à busca.
+func (q *SearchQuery) qualify(name, value string, now time.Time) error {
+	if value == "" {
+		return fmt.Errorf("%s: sem valor", name)
+	}
+	if name == "tag" {
+		tag, err := NormalizeTag(value)
+		if err != nil {
+			return err
+		}
+		q.Tags = append(q.Tags, tag)
+		return nil
+	}
+	day, err := parseSearchDate(value, now)
+	if err != nil {
+		return err
+	}
+	if name == "after" {
+		q.After = day.Unix()
+	} else {
+		q.Before = day.Unix()
+	}
+	return nil
+}
+
+// parseSearchDate devolve o início do dia descrito por value.
+func parseSearchDate(value string, now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch strings.ToLower(value) {
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "last-week":
+		return today.AddDate(0, 0, -7), nil
+	case "last-month":
+		return today.AddDate(0, -1, 0), nil
+	}
+	for _, layout := range searchDateLayouts {
+		if day, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return day, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("data inválida %q (use AAAA-MM-DD, DD/MM/AAAA, today, yesterday, last-week ou last-month)", value)
+}
+
+// lexSearch separa a busca em palavras e frases. Um "-" colado ao termo o
+// exclui, e um "*" logo após a frase busca a última palavra por prefixo.
+func lexSearch(s string) ([]searchToken, error) {
+	var tokens []searchToken
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+		var tok searchToken
+		if runes[i] == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+			tok.neg = true
+			i++
+		}
+		if runes[i] == '"' {
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, errors.New("aspas sem fechamento")
+			}
+			tok.text = string(runes[i+1 : end])
+			tok.phrase = true
+			i = end + 1
+			if i < len(runes) && runes[i] == '*' {
+				tok.prefix = true
+				i++
+			}
+		} else {
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && runes[end] != '"' {
+				end++
+			}
+			tok.text = string(runes[i:end])
+			i = end
+			if trimmed := strings.TrimRight(tok.text, "*"); trimmed != tok.text {
+				tok.text = trimmed
+				tok.prefix = true
+			}
+		}
+		tokens = append(tokens, tok)
+	}
+	return tokens, nil
+}
+
+// ftsTerm escreve o termo como string do FTS5, que nunca é lida como
+// operador, ou devolve "" se ele não tiver letras nem números.
+func ftsTerm(tok searchToken) string {
+	if len(termWords(tok.text)) == 0 {
+		return ""
+	}
+	term := `"` + strings.ReplaceAll(tok.text, `"`, `""`) + `"`
+	if tok.prefix {
+		term += "*"
+	}
+	return term
+}
+
+// termWords separa o texto em palavras, como o tokenizador do FTS.
+func termWords(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// literalSearchQuery procura as palavras de s sem interpretar operadores,
+// aspas ou qualificadores, com a última por prefixo.
+func literalSearchQuery(s string) SearchQuery {
+	var q SearchQuery
+	var match []string
+	for _, field := range strings.Fields(s) {
+		if term := ftsTerm(searchToken{text: field}); term != "" {
+			match = append(match, term)
+			q.Terms = append(q.Terms, termWords(field)...)
+		}
+	}
+	if len(match) > 0 {
+		match[len(match)-1] += "*"
+	}
+	q.Match = strings.Join(match, " ")
+	return q
+}
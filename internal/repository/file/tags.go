@@ -0,0 +1,202 @@
+package file
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidTag indica um nome de tag vazio ou com caracteres não aceitos.
+var ErrInvalidTag = errors.New("tag inválida")
+
+// tagPattern define o nome aceito para uma tag: letras, números, "_", "-" e
+// "/", começando por letra, número ou "_".
+var tagPattern = regexp.MustCompile(`^[\p{L}\p{N}_][\p{L}\p{N}_/-]*$`)
+
+// inlineTagPattern encontra "#tag" no texto. Exige início de linha ou espaço
+// antes do "#", para não confundir com títulos markdown ("# Título") nem com
+// âncoras de URL.
+var inlineTagPattern = regexp.MustCompile(`(?:^|\s)#([\p{L}\p{N}_][\p{L}\p{N}_/-]*)`)
+
+// Tag é uma tag em uso e a quantidade de notas marcadas com ela.
+type Tag struct {
+	Name  string
+	Count int
+}
+
+// NormalizeTag remove o "#" inicial e espaços e converte para minúsculas,
+// retornando ErrInvalidTag se o nome não for aceito.
+func NormalizeTag(tag string) (string, error) {
+	name := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+	if !tagPattern.MatchString(name) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidTag, tag)
+	}
+	return name, nil
+}
+
+// ExtractTags retorna as tags "#tag" escritas no texto, normalizadas, sem
+// repetição e em ordem alfabética.
+func ExtractTags(text string) []string {
+	seen := map[string]bool{}
+	var tags []string
+	for _, match := range inlineTagPattern.FindAllStringSubmatch(text, -1) {
+		name, err := NormalizeTag(match[1])
+		if err != nil || seen[name] {
+			continue
+		}
+		seen[name] = true
+		tags = append(tags, name)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// noteTagsColumn lista as tags da nota "n" separadas por espaço, já que os
+// nomes aceitos por tagPattern nunca contêm espaços.
+const noteTagsColumn = `COALESCE((SELECT group_concat(t.name, ' ')
+		FROM note_tags nt JOIN tags t ON t.id = nt.tag_id
+		WHERE nt.note_id = n.id), '')`
+
+func splitTags(column string) []string {
+	if column == "" {
+		return nil
+	}
+	tags := strings.Fields(column)
+	sort.Strings(tags)
+	return tags
+}
+
+// tagConditions monta uma condição por tag, exigindo que a nota "n" tenha
+// todas elas.
+func tagConditions(tags []string) ([]string, []any) {
+	var conds []string
+	var args []any
+	for _, tag := range tags {
+		conds = append(conds, `EXISTS (SELECT 1 FROM note_tags nt JOIN tags t ON t.id = nt.tag_id
+			WHERE nt.note_id = n.id AND t.name = ?)`)
+		args = append(args, tag)
+	}
+	return conds, args
+}
+
+func ensureTag(ctx context.Context, tx *sql.Tx, name string) error {
+	_, err := tx.ExecContext(ctx, `INSERT INTO tags (name) VALUES (?) ON CONFLICT(name) DO NOTHING`, name)
+	return err
+}
+
+// syncInlineTags alinha as tags vindas de "#tag" com o texto atual da nota.
+// Tags adicionadas por AddTag não são removidas quando somem do texto.
+func syncInlineTags(ctx context.Context, tx *sql.Tx, noteId int64, text string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM note_tags WHERE note_id = ? AND inline = 1`, noteId); err != nil {
+		return fmt.Errorf("erro ao limpar tags da nota %d: %v", noteId, err)
+	}
+	for _, name := range ExtractTags(text) {
+		if err := ensureTag(ctx, tx, name); err != nil {
+			return fmt.Errorf("erro ao criar tag %q: %v", name, err)
+		}
+		_, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO note_tags (note_id, tag_id, inline)
+				SELECT ?, id, 1 FROM tags WHERE name = ?
+				ON CONFLICT(note_id, tag_id) DO NOTHING`,
+			noteId, name,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao marcar nota %d com %q: %v", noteId, name, err)
+		}
+	}
+	return nil
+}
+
+// AddTag marca a nota com a tag, criando a tag se necessário. Retorna
+// sql.ErrNoRows se a nota não existir ou estiver na lixeira.
+func (s SqliteHandler) AddTag(ctx context.Context, noteId int, tag string) error {
+	name, err := NormalizeTag(tag)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var exists int
+	if err := tx.QueryRowContext(ctx, `SELECT 1 FROM notas WHERE id = ? AND deleted_at = 0`, noteId).Scan(&exists); err != nil {
+		return err
+	}
+	if err := addTag(ctx, tx, int64(noteId), name); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// addTag marca a nota com a tag já normalizada, dentro de tx.
+func addTag(ctx context.Context, tx *sql.Tx, noteId int64, name string) error {
+	if err := ensureTag(ctx, tx, name); err != nil {
+		return err
+	}
+	// Uma tag adicionada manualmente deixa de depender do texto da nota.
+	_, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO note_tags (note_id, tag_id, inline)
+			SELECT ?, id, 0 FROM tags WHERE name = ?
+			ON CONFLICT(note_id, tag_id) DO UPDATE SET inline = 0`,
+		noteId, name,
+	)
+	return err
+}
+
+// RemoveTag desmarca a nota, se ela não estiver na lixeira. Se a tag ainda
+// estiver escrita no texto como "#tag", ela volta na próxima vez que a nota for
+// salva.
+func (s SqliteHandler) RemoveTag(ctx context.Context, noteId int, tag string) (int64, error) {
+	name, err := NormalizeTag(tag)
+	if err != nil {
+		return 0, err
+	}
+	row, err := s.DB.ExecContext(
+		ctx,
+		`DELETE FROM note_tags
+			WHERE note_id = (SELECT id FROM notas WHERE id = ? AND deleted_at = 0)
+				AND tag_id = (SELECT id FROM tags WHERE name = ?)`,
+		noteId, name,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return row.RowsAffected()
+}
+
+// ListTags retorna as tags associadas a ao menos uma nota fora da lixeira, em
+// ordem alfabética.
+func (s SqliteHandler) ListTags(ctx context.Context) ([]Tag, error) {
+	rows, err := s.DB.QueryContext(
+		ctx,
+		`SELECT t.name, COUNT(*)
+			FROM tags t
+			INNER JOIN note_tags nt ON nt.tag_id = t.id
+			INNER JOIN notas n ON n.id = nt.note_id AND n.deleted_at = 0
+			GROUP BY t.id
+			ORDER BY t.name`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []Tag
+	for rows.Next() {
+		var tag Tag
+		if err := rows.Scan(&tag.Name, &tag.Count); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
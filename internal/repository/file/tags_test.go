@@ -0,0 +1,162 @@
+package file_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gustavo-silva98/adnotes/internal/repository/file"
+)
+
+func TestExtractTags(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"sem tags", nil},
+		{"#projeto reunião de #Equipe", []string{"equipe", "projeto"}},
+		{"# Título markdown\n## Subtítulo", nil},
+		{"link http://site.com/#ancora", nil},
+		{"#cliente/acme e #cliente/acme de novo", []string{"cliente/acme"}},
+		{"linha\n#ação", []string{"ação"}},
+	}
+	for _, tt := range tests {
+		if got := file.ExtractTags(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ExtractTags(%q) = %v, esperado %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeTag(t *testing.T) {
+	if got, err := file.NormalizeTag(" #Projeto "); err != nil || got != "projeto" {
+		t.Errorf("NormalizeTag = %q, %v", got, err)
+	}
+	for _, invalid := range []string{"", "#", "com espaço", "-inicio"} {
+		if _, err := file.NormalizeTag(invalid); !errors.Is(err, file.ErrInvalidTag) {
+			t.Errorf("Esperado ErrInvalidTag para %q, obtido %v", invalid, err)
+		}
+	}
+}
+
+func TestInlineTagsFollowEdits(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+
+	id, err := handler.InsertNote(&file.Note{CreatedAt: 1, NoteText: "reunião #projeto #equipe"}, ctx)
+	if err != nil {
+		t.Fatalf("Erro ao inserir nota: %v", err)
+	}
+	note, _ := handler.GetNote(ctx, int(id))
+	if !reflect.DeepEqual(note.Tags, []string{"equipe", "projeto"}) {
+		t.Errorf("Tags inline não extraídas: %v", note.Tags)
+	}
+
+	if err := handler.AddTag(ctx, int(id), "manual"); err != nil {
+		t.Fatalf("Erro ao adicionar tag: %v", err)
+	}
+	note.NoteText = "reunião #projeto"
+	if _, err := handler.UpdateEditNoteRepository(ctx, note); err != nil {
+		t.Fatalf("Erro ao editar nota: %v", err)
+	}
+	note, _ = handler.GetNote(ctx, int(id))
+	if !reflect.DeepEqual(note.Tags, []string{"manual", "projeto"}) {
+		t.Errorf("Edição deveria remover só a tag inline apagada: %v", note.Tags)
+	}
+}
+
+func TestInsertNoteWithTags(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+
+	id, err := handler.InsertNote(&file.Note{CreatedAt: 1, NoteText: "deploy #infra", Tags: []string{"#Urgente", "infra"}}, ctx)
+	if err != nil {
+		t.Fatalf("Erro ao inserir nota: %v", err)
+	}
+	note, _ := handler.GetNote(ctx, int(id))
+	if !reflect.DeepEqual(note.Tags, []string{"infra", "urgente"}) {
+		t.Errorf("Tags da inserção não gravadas: %v", note.Tags)
+	}
+
+	if _, err := handler.InsertNote(&file.Note{CreatedAt: 1, NoteText: "outra", Tags: []string{"ok", "com espaço"}}, ctx); !errors.Is(err, file.ErrInvalidTag) {
+		t.Errorf("Esperado ErrInvalidTag, obtido %v", err)
+	}
+	if total, _ := handler.GetTotalCount(ctx, file.NoteFilter{}); total != 1 {
+		t.Errorf("Tag inválida não deveria gravar a nota: %d notas", total)
+	}
+}
+
+func TestAddRemoveAndListTags(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+
+	a, _ := handler.InsertNote(&file.Note{CreatedAt: 1, NoteText: "a"}, ctx)
+	b, _ := handler.InsertNote(&file.Note{CreatedAt: 1, NoteText: "b #projeto"}, ctx)
+
+	if err := handler.AddTag(ctx, int(a), "#Projeto"); err != nil {
+		t.Fatalf("Erro ao adicionar tag: %v", err)
+	}
+	if err := handler.AddTag(ctx, 999, "projeto"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Esperado sql.ErrNoRows para nota inexistente, obtido %v", err)
+	}
+
+	tags, err := handler.ListTags(ctx)
+	if err != nil {
+		t.Fatalf("Erro ao listar tags: %v", err)
+	}
+	if len(tags) != 1 || tags[0] != (file.Tag{Name: "projeto", Count: 2}) {
+		t.Errorf("ListTags inesperado: %v", tags)
+	}
+
+	removed, err := handler.RemoveTag(ctx, int(a), "projeto")
+	if err != nil || removed != 1 {
+		t.Fatalf("Erro ao remover tag: %v (%d linhas)", err, removed)
+	}
+	if _, err := handler.DeleteNoteRepository(ctx, int(b)); err != nil {
+		t.Fatalf("Erro ao deletar nota: %v", err)
+	}
+	tags, _ = handler.ListTags(ctx)
+	if len(tags) != 0 {
+		t.Errorf("Tags sem notas não deveriam ser listadas: %v", tags)
+	}
+}
+
+func TestTagFilterOnQueryAndSearch(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+
+	tagged, _ := handler.InsertNote(&file.Note{CreatedAt: 1, NoteText: "relatório #projeto"}, ctx)
+	_, _ = handler.InsertNote(&file.Note{CreatedAt: 1, NoteText: "relatório pessoal"}, ctx)
+
+	filter := file.NoteFilter{Tag: "projeto"}
+	notes, err := handler.QueryNotes(ctx, file.QueryOptions{NoteFilter: filter})
+	if err != nil {
+		t.Fatalf("Erro na consulta filtrada: %v", err)
+	}
+	if len(notes) != 1 || notes[0].ID != int(tagged) {
+		t.Errorf("Filtro por tag retornou %v", notes)
+	}
+	if total, _ := handler.GetTotalCount(ctx, filter); total != 1 {
+		t.Errorf("Contagem filtrada esperada 1, obtida %d", total)
+	}
+
+	for _, query := range []string{"relat tag:projeto", "tag:Projeto"} {
+		results, err := handler.FullSearchNote(ctx, query, file.NoteFilter{})
+		if err != nil {
+			t.Fatalf("Erro na busca %q: %v", query, err)
+		}
+		if len(results) != 1 || results[0].ID != int(tagged) {
+			t.Errorf("Busca %q retornou %v", query, results)
+		}
+	}
+
+	results, _ := handler.FullSearchNote(ctx, "relat", filter)
+	if len(results) != 1 {
+		t.Errorf("Busca com filtro de tag retornou %v", results)
+	}
+	results, _ = handler.FullSearchNote(ctx, "relat", file.NoteFilter{})
+	if len(results) != 2 {
+		t.Errorf("Busca sem filtro deveria retornar as duas notas, obtido %v", results)
+	}
+}
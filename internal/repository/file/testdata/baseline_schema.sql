@@ -0,0 +1,27 @@
+-- Schema criado pelo InitDB antes do sistema de migrações (user_version = 0).
+CREATE TABLE IF NOT EXISTS notas (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	hour INTEGER NOT NULL,
+	note_text TEXT NOT NULL,
+	reminder INTEGER,
+	plusreminder INTEGER
+);
+
+CREATE VIRTUAL TABLE IF NOT EXISTS notes_fts USING fts5(note_text_fts);
+
+CREATE TRIGGER IF NOT EXISTS notes_ai AFTER INSERT ON notas BEGIN
+	INSERT INTO notes_fts(rowid,note_text_fts) VALUES (new.id, new.note_text);
+END;
+
+CREATE TRIGGER IF NOT EXISTS notes_ad AFTER DELETE ON notas BEGIN
+	DELETE FROM notes_fts WHERE rowid = old.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS notes_au AFTER UPDATE ON notas BEGIN
+	DELETE FROM notes_fts WHERE rowid = old.id;
+	INSERT INTO notes_fts(rowid,note_text_fts) VALUES (new.id, new.note_text);
+END;
+
+INSERT INTO notas (hour, note_text, reminder, plusreminder) VALUES (1700000000, 'Primeira anotação do fixture', 0, 0);
+INSERT INTO notas (hour, note_text, reminder, plusreminder) VALUES (1700000060, 'Segunda nota, com busca', 0, 0);
+INSERT INTO notas (hour, note_text, reminder, plusreminder) VALUES (1700000120, 'Terceira nota #Fixture', 0, 0);
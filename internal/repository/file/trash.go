@@ -0,0 +1,57 @@
+package file
+
+import "context"
+
+// ListTrash retorna as notas da lixeira, da excluída mais recentemente para a mais antiga.
+func (s SqliteHandler) ListTrash(ctx context.Context) ([]Note, error) {
+	rows, err := s.DB.QueryContext(
+		ctx,
+		`SELECT `+noteColumns+`
+			FROM notas n
+			WHERE n.deleted_at > 0
+			ORDER BY n.deleted_at DESC, n.id DESC`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notes []Note
+	for rows.Next() {
+		note, err := scanNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, note)
+	}
+	return notes, rows.Err()
+}
+
+// RestoreNote tira a nota da lixeira. Retorna 0 se ela não estiver na lixeira.
+func (s SqliteHandler) RestoreNote(ctx context.Context, noteId int) (int64, error) {
+	row, err := s.DB.ExecContext(ctx, `UPDATE notas SET deleted_at = 0 WHERE id = ? AND deleted_at > 0`, noteId)
+	if err != nil {
+		return 0, err
+	}
+	return row.RowsAffected()
+}
+
+// PurgeNote apaga definitivamente uma nota da lixeira; os triggers removem
+// também o índice FTS, as tags e o histórico. Notas fora da lixeira não são
+// afetadas.
+func (s SqliteHandler) PurgeNote(ctx context.Context, noteId int) (int64, error) {
+	row, err := s.DB.ExecContext(ctx, `DELETE FROM notas WHERE id = ? AND deleted_at > 0`, noteId)
+	if err != nil {
+		return 0, err
+	}
+	return row.RowsAffected()
+}
+
+// PurgeTrash apaga definitivamente as notas que foram para a lixeira até before (unix).
+func (s SqliteHandler) PurgeTrash(ctx context.Context, before int64) (int64, error) {
+	row, err := s.DB.ExecContext(ctx, `DELETE FROM notas WHERE deleted_at > 0 AND deleted_at <= ?`, before)
+	if err != nil {
+		return 0, err
+	}
+	return row.RowsAffected()
+}
@@ -0,0 +1,125 @@
+package file_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/gustavo-silva98/adnotes/internal/repository/file"
+)
+
+func TestDeleteMovesNoteToTrash(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+
+	keep, _ := handler.InsertNote(&file.Note{CreatedAt: 1, NoteText: "fica #a"}, ctx)
+	gone, _ := handler.InsertNote(&file.Note{CreatedAt: 2, NoteText: "lixo #a", Reminder: 10}, ctx)
+
+	if n, err := handler.DeleteNoteRepository(ctx, int(gone)); err != nil || n != 1 {
+		t.Fatalf("DeleteNoteRepository = %d, %v", n, err)
+	}
+	if n, _ := handler.DeleteNoteRepository(ctx, int(gone)); n != 0 {
+		t.Errorf("Nota já na lixeira não deveria ser excluída de novo")
+	}
+
+	notes, _ := handler.QueryNotes(ctx, file.QueryOptions{})
+	if len(notes) != 1 || notes[0].ID != int(keep) {
+		t.Errorf("QueryNotes não deveria trazer notas da lixeira: %v", notes)
+	}
+	if total, _ := handler.GetTotalCount(ctx, file.NoteFilter{}); total != 1 {
+		t.Errorf("GetTotalCount = %d, esperado 1", total)
+	}
+	found, _ := handler.FullSearchNote(ctx, "lixo", file.NoteFilter{})
+	if len(found) != 0 {
+		t.Errorf("FullSearchNote não deveria trazer notas da lixeira: %v", found)
+	}
+	if _, err := handler.GetNote(ctx, int(gone)); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetNote de nota na lixeira: %v", err)
+	}
+	if due, _ := handler.DueReminders(ctx, 100); len(due) != 0 {
+		t.Errorf("Lembrete de nota na lixeira não deveria disparar: %v", due)
+	}
+	if tags, _ := handler.ListTags(ctx); len(tags) != 1 || tags[0].Count != 1 {
+		t.Errorf("ListTags deveria ignorar a lixeira: %v", tags)
+	}
+
+	trash, err := handler.ListTrash(ctx)
+	if err != nil || len(trash) != 1 || trash[0].ID != int(gone) || trash[0].DeletedAt == 0 {
+		t.Fatalf("ListTrash = %+v, %v", trash, err)
+	}
+
+	if n, err := handler.RestoreNote(ctx, int(gone)); err != nil || n != 1 {
+		t.Fatalf("RestoreNote = %d, %v", n, err)
+	}
+	if n, _ := handler.RestoreNote(ctx, int(keep)); n != 0 {
+		t.Errorf("RestoreNote não deveria afetar nota fora da lixeira")
+	}
+	found, _ = handler.FullSearchNote(ctx, "lixo", file.NoteFilter{})
+	if len(found) != 1 {
+		t.Errorf("Nota restaurada deveria voltar à busca: %v", found)
+	}
+}
+
+func TestTrashedNoteIsReadOnly(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+
+	id, _ := handler.InsertNote(&file.Note{CreatedAt: 1, NoteText: "lixo #a"}, ctx)
+	nb, _ := handler.CreateNotebook(ctx, "Outro", 0)
+	if _, err := handler.DeleteNoteRepository(ctx, int(id)); err != nil {
+		t.Fatal(err)
+	}
+
+	edit := file.Note{ID: int(id), UpdatedAt: 2, NoteText: "editada", Reminder: 10}
+	if n, err := handler.UpdateEditNoteRepository(ctx, edit); err != nil || n != 0 {
+		t.Errorf("UpdateEditNoteRepository = %d, %v", n, err)
+	}
+	if n, err := handler.SetReminder(ctx, int(id), 10, 0); err != nil || n != 0 {
+		t.Errorf("SetReminder = %d, %v", n, err)
+	}
+	if n, err := handler.MoveNote(ctx, int(id), int(nb)); err != nil || n != 0 {
+		t.Errorf("MoveNote = %d, %v", n, err)
+	}
+	if err := handler.AddTag(ctx, int(id), "b"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("AddTag: esperado sql.ErrNoRows, obtido %v", err)
+	}
+	if n, err := handler.RemoveTag(ctx, int(id), "a"); err != nil || n != 0 {
+		t.Errorf("RemoveTag = %d, %v", n, err)
+	}
+
+	handler.RestoreNote(ctx, int(id))
+	note, _ := handler.GetNote(ctx, int(id))
+	if note.NoteText != "lixo #a" || note.Reminder != 0 || note.NotebookID != file.InboxNotebookID || len(note.Tags) != 1 {
+		t.Errorf("Nota na lixeira não deveria ter mudado: %+v", note)
+	}
+	if due, _ := handler.DueReminders(ctx, 100); len(due) != 0 {
+		t.Errorf("Lembrete não deveria ter sido agendado: %v", due)
+	}
+}
+
+func TestPurgeTrash(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+
+	old, _ := handler.InsertNote(&file.Note{CreatedAt: 1, NoteText: "antiga"}, ctx)
+	recent, _ := handler.InsertNote(&file.Note{CreatedAt: 2, NoteText: "recente"}, ctx)
+	live, _ := handler.InsertNote(&file.Note{CreatedAt: 3, NoteText: "viva"}, ctx)
+	handler.DeleteNoteRepository(ctx, int(old))
+	handler.DeleteNoteRepository(ctx, int(recent))
+	handler.DB.ExecContext(ctx, `UPDATE notas SET deleted_at = 100 WHERE id = ?`, old)
+
+	if n, err := handler.PurgeNote(ctx, int(live)); err != nil || n != 0 {
+		t.Errorf("PurgeNote não deveria apagar nota fora da lixeira: %d, %v", n, err)
+	}
+	if n, err := handler.PurgeTrash(ctx, 200); err != nil || n != 1 {
+		t.Fatalf("PurgeTrash = %d, %v", n, err)
+	}
+	trash, _ := handler.ListTrash(ctx)
+	if len(trash) != 1 || trash[0].ID != int(recent) {
+		t.Errorf("Só a nota excluída antes do limite deveria ser apagada: %+v", trash)
+	}
+	if n, _ := handler.PurgeNote(ctx, int(recent)); n != 1 {
+		t.Errorf("PurgeNote deveria apagar a nota da lixeira")
+	}
+}
@@ -3,21 +3,50 @@ package file
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
-	_ "modernc.org/sqlite"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
 )
 
 type Writer interface {
 	InsertNote(n *Note, ctx context.Context) (int64, error)
-	QueryNote(limit int, offset int, ctx context.Context) (map[int]Note, error)
+	QueryNotes(ctx context.Context, opts QueryOptions) ([]Note, error)
 	UpdateEditNoteRepository(ctx context.Context, note Note) (int64, error)
 	DeleteNoteRepository(ctx context.Context, noteId int) (int64, error)
-	FullSearchNote(ctx context.Context, argQuery string) (map[int]Note, error)
-	GetTotalCount(ctx context.Context) (int, error)
+	FullSearchNote(ctx context.Context, argQuery string, filter NoteFilter) ([]SearchResult, error)
+	FuzzySearchNote(ctx context.Context, argQuery string, filter NoteFilter) ([]SearchResult, error)
+	SaveSearch(ctx context.Context, name string, query string) (int64, error)
+	ListSavedSearches(ctx context.Context) ([]SavedSearch, error)
+	DeleteSavedSearch(ctx context.Context, searchID int) (int64, error)
+	GetTotalCount(ctx context.Context, filter NoteFilter) (int, error)
+	GetNote(ctx context.Context, noteId int) (Note, error)
+	DueReminders(ctx context.Context, now int64) ([]Note, error)
+	SetReminder(ctx context.Context, noteId int, reminder int, plusReminder int) (int64, error)
+	AddTag(ctx context.Context, noteId int, tag string) error
+	RemoveTag(ctx context.Context, noteId int, tag string) (int64, error)
+	ListTags(ctx context.Context) ([]Tag, error)
+	CreateNotebook(ctx context.Context, name string, parentID int) (int64, error)
+	ListNotebooks(ctx context.Context) ([]Notebook, error)
+	RenameNotebook(ctx context.Context, notebookID int, name string) (int64, error)
+	DeleteNotebook(ctx context.Context, notebookID int) (int64, error)
+	MoveNote(ctx context.Context, noteId int, notebookID int) (int64, error)
+	EachNote(ctx context.Context, filter NoteFilter, fn func(Note) error) error
+	ListRevisions(ctx context.Context, noteId int) ([]Revision, error)
+	GetRevision(ctx context.Context, revisionID int) (Revision, error)
+	RestoreRevision(ctx context.Context, revisionID int, hour int64) (int64, error)
+	ListTrash(ctx context.Context) ([]Note, error)
+	RestoreNote(ctx context.Context, noteId int) (int64, error)
+	PurgeNote(ctx context.Context, noteId int) (int64, error)
+	PurgeTrash(ctx context.Context, before int64) (int64, error)
+	PinNote(ctx context.Context, noteId int) (int64, error)
+	UnpinNote(ctx context.Context, noteId int) (int64, error)
 }
 
 type SqliteHandler struct {
@@ -26,55 +55,131 @@ type SqliteHandler struct {
 	DB        *sql.DB
 }
 
+// Note é uma anotação. CreatedAt e UpdatedAt são os instantes (unix, segundos)
+// da criação e da última edição; na inserção, o que estiver zerado recebe o
+// valor do outro. Reminder guarda o instante (unix, segundos) em que o
+// lembrete dispara, ou 0 quando não há lembrete; PlusReminder é o intervalo de
+// repetição em segundos, ou 0 para lembretes de disparo único. NotebookID é o
+// caderno da nota; 0 na inserção grava no Inbox. Tags é preenchido nas
+// consultas; na inserção, são gravadas como se viessem de AddTag, e depois as
+// tags vêm de "#tag" no texto ou de AddTag.
+// DeletedAt é o instante (unix) em que a nota foi para a lixeira, ou 0.
+// Pinned marca as notas fixadas no topo da leitura. Title é o título
+// explícito; vazio, o título exibido vem do texto (veja notetitle.Of).
 type Note struct {
 	ID           int
-	Hour         int64
+	CreatedAt    int64
+	UpdatedAt    int64
+	Title        string
 	NoteText     string
 	Reminder     int
 	PlusReminder int
+	NotebookID   int
+	Tags         []string
+	DeletedAt    int64
+	Pinned       bool
+}
+
+// noteColumns são as colunas lidas por scanNote, a partir da tabela "notas n".
+const noteColumns = `n.id, n.created_at, n.updated_at, n.title, n.note_text, COALESCE(n.reminder, 0), COALESCE(n.plusreminder, 0), n.notebook_id, n.deleted_at, n.pinned, ` + noteTagsColumn
+
+// scanNote lê uma linha selecionada com noteColumns. Colunas selecionadas
+// depois delas são lidas em extra, na mesma ordem.
+func scanNote(row interface{ Scan(dest ...any) error }, extra ...any) (Note, error) {
+	var note Note
+	var tags string
+	dest := []any{&note.ID, &note.CreatedAt, &note.UpdatedAt, &note.Title, &note.NoteText, &note.Reminder, &note.PlusReminder, &note.NotebookID, &note.DeletedAt, &note.Pinned, &tags}
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return Note{}, err
+	}
+	note.Tags = splitTags(tags)
+	return note, nil
+}
+
+// DataDir é a pasta onde ficam o banco, os logs e os demais arquivos locais.
+var DataDir = filepath.Join("..", "data")
+
+// DataPath retorna o caminho de um arquivo dentro de DataDir.
+func DataPath(name string) string {
+	return filepath.Join(DataDir, name)
+}
+
+// sqlitePragmas valem para toda conexão aberta por InitDB. O banco é
+// compartilhado entre o server (lembretes, lixeira, API), o TUI e a CLI, cada
+// um em seu processo: no modo WAL leituras não bloqueiam a escrita, e
+// busy_timeout faz uma escrita esperar a outra em vez de falhar na hora com
+// SQLITE_BUSY. As transações começam com BEGIN IMMEDIATE para que a espera
+// aconteça no início; uma transação que lê antes de escrever receberia
+// SQLITE_BUSY sem esperar se outro processo gravasse no meio.
+const sqlitePragmas = "_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate"
+
+// IsBusy indica que o banco continuou ocupado por outro processo mesmo depois
+// de busy_timeout; a operação pode ser repetida.
+func IsBusy(err error) bool {
+	var se *sqlite.Error
+	return errors.As(err, &se) && se.Code()&0xff == sqlite3.SQLITE_BUSY
 }
 
 func InitDB(pathString string, ctx context.Context) (*SqliteHandler, error) {
-	db, err := sql.Open("sqlite", pathString)
+	sep := "?"
+	if strings.Contains(pathString, "?") {
+		sep = "&"
+	}
+	db, err := sql.Open("sqlite", pathString+sep+sqlitePragmas)
 	if err != nil {
 		return nil, err
 	}
-	_, err = db.ExecContext(
-		ctx,
-		`CREATE TABLE IF NOT EXISTS notas (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			hour INTEGER NOT NULL,
-			note_text TEXT NOT NULL,
-			reminder INTEGER,
-			plusreminder INTEGER
-		)`,
-	)
-	if err != nil {
+	// O SQLite serializa escritas de qualquer forma; uma única conexão evita
+	// que bancos ":memory:" sejam abertos em duplicidade pelo pool.
+	db.SetMaxOpenConns(1)
+
+	if err := Migrate(ctx, db, migrations); err != nil {
+		db.Close()
 		return nil, err
 	}
+
 	sql_db := &SqliteHandler{
 		DbPath:    pathString,
 		TableName: "notas",
 		DB:        db,
 	}
-	err = sql_db.CreateFTSTable()
-	if err != nil {
-		return nil, err
-	}
-	err = sql_db.CreateFTSTriggers(ctx)
-	if err != nil {
-		return nil, err
-	}
 
 	return sql_db, nil
 }
 
+// InsertNote grava a nota com suas tags em uma só transação; uma tag inválida
+// em n.Tags impede a inserção.
 func (s SqliteHandler) InsertNote(n *Note, ctx context.Context) (int64, error) {
+	tags := make([]string, len(n.Tags))
+	for i, tag := range n.Tags {
+		var err error
+		if tags[i], err = NormalizeTag(tag); err != nil {
+			return 0, err
+		}
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
 
-	res, err := s.DB.ExecContext(
+	notebookID := n.NotebookID
+	if notebookID == 0 {
+		notebookID = InboxNotebookID
+	}
+	createdAt, updatedAt := n.CreatedAt, n.UpdatedAt
+	if createdAt == 0 {
+		createdAt = updatedAt
+	}
+	if updatedAt == 0 {
+		updatedAt = createdAt
+	}
+	res, err := tx.ExecContext(
 		ctx,
-		`INSERT INTO notas (hour, note_text, reminder, plusreminder) VALUES (?, ?, ?, ?)`,
-		n.Hour, n.NoteText, n.Reminder, n.PlusReminder,
+		`INSERT INTO notas (created_at, updated_at, title, note_text, reminder, plusreminder, notebook_id, pinned) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		createdAt, updatedAt, strings.TrimSpace(n.Title), n.NoteText, n.Reminder, n.PlusReminder, notebookID, n.Pinned,
 	)
 	if err != nil {
 		return 0, err
@@ -83,36 +188,23 @@ func (s SqliteHandler) InsertNote(n *Note, ctx context.Context) (int64, error) {
 	if err != nil {
 		return 0, err
 	}
-	return id, nil
-}
-
-func (s SqliteHandler) QueryNote(limit int, offset int, ctx context.Context) (map[int]Note, error) {
-	rows, err := s.DB.QueryContext(
-		ctx,
-		`SELECT * FROM notas ORDER BY id DESC LIMIT ? OFFSET ?`,
-		limit, offset,
-	)
-	if err != nil {
-		return nil, err
+	if err := syncInlineTags(ctx, tx, id, n.NoteText); err != nil {
+		return 0, err
 	}
-	defer rows.Close()
-	var queryMap = map[int]Note{}
-	for rows.Next() {
-		var note Note
-		err := rows.Scan(&note.ID, &note.Hour, &note.NoteText, &note.Reminder, &note.PlusReminder)
-		if err != nil {
-			return nil, err
+	for _, name := range tags {
+		if err := addTag(ctx, tx, id, name); err != nil {
+			return 0, fmt.Errorf("erro ao marcar nota %d com %q: %v", id, name, err)
 		}
-		queryMap[note.ID] = note
 	}
-
-	return queryMap, nil
-
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
 }
 
 func WriteLog(msg string, logFilePath string) {
 	if logFilePath == "" {
-		logFilePath = filepath.Join("..", "/data/logs.txt")
+		logFilePath = DataPath("logs.txt")
 	}
 
 	file, err := os.OpenFile(logFilePath, os.O_APPEND|os.O_RDWR|os.O_CREATE, 0644)
@@ -128,10 +220,16 @@ func WriteLog(msg string, logFilePath string) {
 	log.Println(msg)
 }
 
-func (s SqliteHandler) GetTotalCount(ctx context.Context) (int, error) {
+func (s SqliteHandler) GetTotalCount(ctx context.Context, filter NoteFilter) (int, error) {
+	conds, args, err := filter.conditions()
+	if err != nil {
+		return 0, err
+	}
+
 	row, err := s.DB.QueryContext(
 		ctx,
-		fmt.Sprintf(`SELECT COUNT(*) FROM %v`, s.TableName),
+		fmt.Sprintf(`SELECT COUNT(*) FROM %v n %v`, s.TableName, whereClause(conds)),
+		args...,
 	)
 
 	if err != nil {
@@ -148,12 +246,18 @@ func (s SqliteHandler) GetTotalCount(ctx context.Context) (int, error) {
 }
 
 func (s SqliteHandler) UpdateEditNoteRepository(ctx context.Context, note Note) (int64, error) {
-	row, err := s.DB.ExecContext(
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	row, err := tx.ExecContext(
 		ctx,
 		`UPDATE notas
-		SET hour = ?, note_text = ?, reminder = ?, plusreminder = ?
-		WHERE id = ?`,
-		note.Hour, note.NoteText, note.Reminder, note.PlusReminder, note.ID)
+		SET updated_at = ?, title = ?, note_text = ?, reminder = ?, plusreminder = ?
+		WHERE id = ? AND deleted_at = 0`,
+		note.UpdatedAt, strings.TrimSpace(note.Title), note.NoteText, note.Reminder, note.PlusReminder, note.ID)
 	if err != nil {
 		return 0, err
 	}
@@ -161,14 +265,28 @@ func (s SqliteHandler) UpdateEditNoteRepository(ctx context.Context, note Note)
 	if err != nil {
 		return 0, err
 	}
+	if ra > 0 {
+		if err := syncInlineTags(ctx, tx, int64(note.ID), note.NoteText); err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
 
 	return ra, nil
 
 }
 
+// DeleteNoteRepository move a nota para a lixeira. Ela some das consultas,
+// mas pode voltar com RestoreNote até ser removida por PurgeNote ou PurgeTrash.
 func (s SqliteHandler) DeleteNoteRepository(ctx context.Context, noteId int) (int64, error) {
 
-	row, err := s.DB.ExecContext(ctx, `DELETE FROM notas WHERE id = ?`, noteId)
+	row, err := s.DB.ExecContext(
+		ctx,
+		`UPDATE notas SET deleted_at = ? WHERE id = ? AND deleted_at = 0`,
+		time.Now().Unix(), noteId,
+	)
 	if err != nil {
 		return 0, err
 	}
@@ -180,73 +298,125 @@ func (s SqliteHandler) DeleteNoteRepository(ctx context.Context, noteId int) (in
 	return ra, nil
 }
 
-func (s SqliteHandler) CreateFTSTable() error {
-	createFTSQuery := `CREATE VIRTUAL TABLE IF NOT EXISTS notes_fts USING fts5(note_text_fts);`
+const createFTSTableQuery = `CREATE VIRTUAL TABLE IF NOT EXISTS notes_fts USING fts5(note_text_fts);`
 
-	_, err := s.DB.Exec(createFTSQuery)
-	if err != nil {
-		return fmt.Errorf("erro ao criar tabela FTS: %v", err)
-	}
-	return nil
-}
-
-func (s SqliteHandler) CreateFTSTriggers(ctx context.Context) error {
-	triggerInsert := `
+const triggerInsertQuery = `
 	CREATE TRIGGER IF NOT EXISTS notes_ai AFTER INSERT ON notas BEGIN 
 		INSERT INTO notes_fts(rowid,note_text_fts) VALUES (new.id, new.note_text);
 	END;`
 
-	triggerDelete := `
+const triggerDeleteQuery = `
 	CREATE TRIGGER IF NOT EXISTS notes_ad AFTER DELETE ON notas BEGIN 
 		DELETE FROM notes_fts WHERE rowid = old.id;
 	END;`
 
-	triggerUpdate := `
+const triggerUpdateQuery = `
 	CREATE TRIGGER IF NOT EXISTS notes_au AFTER UPDATE ON notas BEGIN 
 		DELETE FROM notes_fts WHERE rowid = old.id;
 		INSERT INTO notes_fts(rowid,note_text_fts) VALUES (new.id, new.note_text);
 	END;`
 
-	if _, err := s.DB.ExecContext(ctx, triggerInsert); err != nil {
+func (s SqliteHandler) CreateFTSTable() error {
+	_, err := s.DB.Exec(createFTSTableQuery)
+	if err != nil {
+		return fmt.Errorf("erro ao criar tabela FTS: %v", err)
+	}
+	return nil
+}
+
+func (s SqliteHandler) CreateFTSTriggers(ctx context.Context) error {
+	if _, err := s.DB.ExecContext(ctx, triggerInsertQuery); err != nil {
 		return fmt.Errorf("erro ao criar trigger de INSERT: %v", err)
 	}
 
-	if _, err := s.DB.ExecContext(ctx, triggerDelete); err != nil {
+	if _, err := s.DB.ExecContext(ctx, triggerDeleteQuery); err != nil {
 		return fmt.Errorf("erro ao criar trigger de DELETE: %v", err)
 	}
 
-	if _, err := s.DB.ExecContext(ctx, triggerUpdate); err != nil {
+	if _, err := s.DB.ExecContext(ctx, triggerUpdateQuery); err != nil {
 		return fmt.Errorf("erro ao criar trigger de UPDATE: %v", err)
 	}
 	return nil
 }
 
-func (s SqliteHandler) FullSearchNote(ctx context.Context, argQuery string) (map[int]Note, error) {
-	if argQuery != "" {
-		argQuery = argQuery + "*"
-	}
+// GetNote retorna a nota pelo id, ou sql.ErrNoRows se ela não existir ou
+// estiver na lixeira.
+func (s SqliteHandler) GetNote(ctx context.Context, noteId int) (Note, error) {
+	return scanNote(s.DB.QueryRowContext(
+		ctx,
+		`SELECT `+noteColumns+` FROM notas n WHERE n.id = ? AND n.deleted_at = 0`,
+		noteId,
+	))
+}
 
+// DueReminders retorna as notas cujo lembrete já venceu em now, do mais antigo
+// para o mais novo.
+func (s SqliteHandler) DueReminders(ctx context.Context, now int64) ([]Note, error) {
 	rows, err := s.DB.QueryContext(
 		ctx,
-		`SELECT nt.id, nt.hour, fts.note_text_fts, nt.reminder, nt.plusreminder
-			FROM notes_fts fts
-			INNER JOIN notas nt ON nt.id = fts.rowid
-			WHERE fts.note_text_fts MATCH ?`, argQuery,
+		`SELECT `+noteColumns+`
+			FROM notas n
+			WHERE n.reminder > 0 AND n.reminder <= ? AND n.deleted_at = 0
+			ORDER BY n.reminder, n.id`,
+		now,
 	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var queryMap = map[int]Note{}
+	var notes []Note
 	for rows.Next() {
-		var note Note
-		err := rows.Scan(&note.ID, &note.Hour, &note.NoteText, &note.Reminder, &note.PlusReminder)
+		note, err := scanNote(rows)
 		if err != nil {
 			return nil, err
 		}
-		queryMap[note.ID] = note
+		notes = append(notes, note)
+	}
+	return notes, rows.Err()
+}
+
+// SetReminder altera somente o lembrete de uma nota, sem tocar no texto nem na
+// hora. Notas na lixeira não são alteradas.
+func (s SqliteHandler) SetReminder(ctx context.Context, noteId int, reminder int, plusReminder int) (int64, error) {
+	row, err := s.DB.ExecContext(
+		ctx,
+		`UPDATE notas SET reminder = ?, plusreminder = ? WHERE id = ? AND deleted_at = 0`,
+		reminder, plusReminder, noteId,
+	)
+	if err != nil {
+		return 0, err
 	}
+	return row.RowsAffected()
+}
 
-	return queryMap, nil
+// EachNote percorre as notas do filtro em ordem de id, chamando fn para cada
+// uma sem carregar todas em memória. Um erro de fn interrompe a leitura e é
+// retornado. Como o banco usa uma única conexão, fn não pode consultar o banco.
+func (s SqliteHandler) EachNote(ctx context.Context, filter NoteFilter, fn func(Note) error) error {
+	conds, args, err := filter.conditions()
+	if err != nil {
+		return err
+	}
+
+	rows, err := s.DB.QueryContext(
+		ctx,
+		`SELECT `+noteColumns+` FROM notas n `+whereClause(conds)+` ORDER BY n.id`,
+		args...,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		note, err := scanNote(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(note); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
 }
@@ -3,10 +3,13 @@ package file_test
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/gustavo-silva98/adnotes/internal/repository/file"
 )
@@ -28,7 +31,7 @@ func TestInsertNote(t *testing.T) {
 	ctx := context.Background()
 
 	note := &file.Note{
-		Hour:         int64(1),
+		CreatedAt:    int64(1),
 		NoteText:     "Teste de insert",
 		Reminder:     1,
 		PlusReminder: 2,
@@ -43,12 +46,12 @@ func TestInsertNote(t *testing.T) {
 	}
 }
 
-func TestQueryNote(t *testing.T) {
+func TestQueryNotes(t *testing.T) {
 	handler := setupTestDB(t)
 	ctx := context.Background()
 
 	note := &file.Note{
-		Hour:         int64(1),
+		CreatedAt:    int64(1),
 		NoteText:     "Teste de insert",
 		Reminder:     1,
 		PlusReminder: 2,
@@ -56,7 +59,7 @@ func TestQueryNote(t *testing.T) {
 
 	_, _ = handler.InsertNote(note, ctx)
 
-	result, err := handler.QueryNote(10, 0, ctx)
+	result, err := handler.QueryNotes(ctx, file.QueryOptions{Limit: 10})
 	if err != nil {
 		t.Fatalf("Erro ao consultar notas - %v", err)
 	}
@@ -71,7 +74,7 @@ func TestUpdateEditNote(t *testing.T) {
 	ctx := context.Background()
 
 	note := &file.Note{
-		Hour:         int64(1),
+		CreatedAt:    int64(1),
 		NoteText:     "Teste de insert",
 		Reminder:     1,
 		PlusReminder: 2,
@@ -91,11 +94,54 @@ func TestUpdateEditNote(t *testing.T) {
 
 }
 
+func TestEditKeepsCreatedAt(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+
+	id, _ := handler.InsertNote(&file.Note{CreatedAt: 100, NoteText: "original"}, ctx)
+	note, err := handler.GetNote(ctx, int(id))
+	if err != nil || note.CreatedAt != 100 || note.UpdatedAt != 100 {
+		t.Fatalf("Nota nova deveria ter criação e edição iguais: %+v (%v)", note, err)
+	}
+
+	note.NoteText = "editada"
+	note.UpdatedAt = 200
+	if _, err := handler.UpdateEditNoteRepository(ctx, note); err != nil {
+		t.Fatalf("Erro ao editar nota - %v", err)
+	}
+	note, _ = handler.GetNote(ctx, int(id))
+	if note.CreatedAt != 100 || note.UpdatedAt != 200 {
+		t.Errorf("Edição deveria mudar só updated_at: %+v", note)
+	}
+}
+
+func TestNoteTitle(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+
+	id, _ := handler.InsertNote(&file.Note{CreatedAt: 1, Title: "  Runbook de plantão ", NoteText: "passos"}, ctx)
+	note, err := handler.GetNote(ctx, int(id))
+	if err != nil || note.Title != "Runbook de plantão" {
+		t.Fatalf("Título gravado inesperado: %q (%v)", note.Title, err)
+	}
+
+	note.NoteText = "passos revisados"
+	handler.UpdateEditNoteRepository(ctx, note)
+	if note, _ = handler.GetNote(ctx, int(id)); note.Title != "Runbook de plantão" {
+		t.Errorf("Editar o texto não deveria mudar o título: %q", note.Title)
+	}
+
+	untitled, _ := handler.InsertNote(&file.Note{CreatedAt: 1, NoteText: "# Cabeçalho"}, ctx)
+	if note, _ := handler.GetNote(ctx, int(untitled)); note.Title != "" {
+		t.Errorf("Sem título explícito a coluna fica vazia: %q", note.Title)
+	}
+}
+
 func TestDeleteNoteRepository(t *testing.T) {
 	ctx := context.Background()
 	handler, _ := file.InitDB(":memory:", ctx)
 	note := &file.Note{
-		Hour:         int64(1),
+		CreatedAt:    int64(1),
 		NoteText:     "Teste de insert",
 		Reminder:     1,
 		PlusReminder: 2,
@@ -111,13 +157,58 @@ func TestDeleteNoteRepository(t *testing.T) {
 	}
 }
 
+func TestConcurrentWritersWait(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "banco.db")
+	server, err := file.InitDB(path, ctx)
+	if err != nil {
+		t.Fatalf("Erro ao abrir banco - %v", err)
+	}
+	defer server.DB.Close()
+	client, err := file.InitDB(path, ctx)
+	if err != nil {
+		t.Fatalf("Erro ao abrir segundo handle - %v", err)
+	}
+	defer client.DB.Close()
+
+	var mode string
+	if err := client.DB.QueryRow(`PRAGMA journal_mode`).Scan(&mode); err != nil || mode != "wal" {
+		t.Errorf("journal_mode = %q, %v; esperado wal", mode, err)
+	}
+
+	// O server segura uma transação de escrita enquanto o cliente grava.
+	tx, err := server.DB.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO notas (created_at, updated_at, note_text) VALUES (1, 1, 'server')`); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.InsertNote(&file.Note{CreatedAt: 2, NoteText: "cliente"}, ctx)
+		done <- err
+	}()
+	time.Sleep(200 * time.Millisecond)
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("Escrita concorrente deveria esperar o lock, falhou com %v", err)
+	}
+	if total, _ := server.GetTotalCount(ctx, file.NoteFilter{}); total != 2 {
+		t.Errorf("Esperadas 2 notas, obtidas %d", total)
+	}
+}
+
 func BenchmarkInsertNote(b *testing.B) {
 	ctx := context.Background()
 	handler, _ := file.InitDB(":memory:", ctx)
 
 	for i := 0; i < b.N; i++ {
 		note := &file.Note{
-			Hour:         int64(i),
+			CreatedAt:    int64(i),
 			NoteText:     "Benchmark",
 			Reminder:     1,
 			PlusReminder: 2,
@@ -238,7 +329,7 @@ func TestFTSTriggersIntegration(t *testing.T) {
 
 	// Testar trigger de INSERT
 	note := &file.Note{
-		Hour:         123456789,
+		CreatedAt:    123456789,
 		NoteText:     "Teste trigger insert",
 		Reminder:     1,
 		PlusReminder: 2,
@@ -265,6 +356,11 @@ func TestFTSTriggersIntegration(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Erro ao deletar nota: %v", err)
 	}
+	// Na lixeira a nota continua indexada; só a remoção definitiva limpa o FTS.
+	_, err = handler.PurgeNote(ctx, int(id))
+	if err != nil {
+		t.Fatalf("Erro ao apagar nota definitivamente: %v", err)
+	}
 
 	var count int
 	err = handler.DB.QueryRowContext(ctx,
@@ -284,7 +380,7 @@ func TestFullSearchNote(t *testing.T) {
 
 	// Testar trigger de INSERT
 	note := &file.Note{
-		Hour:         123456789,
+		CreatedAt:    123456789,
 		NoteText:     "Teste trigger insert",
 		Reminder:     1,
 		PlusReminder: 2,
@@ -294,17 +390,95 @@ func TestFullSearchNote(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Erro ao inserir nota: %v", err)
 	}
-	results, err := handler.FullSearchNote(ctx, "insert")
+	results, err := handler.FullSearchNote(ctx, "insert", file.NoteFilter{})
 	if err != nil {
 		t.Error("Falha ao realizar busca de notas")
 	}
-	val, ok := results[int(id)]
-	if !ok {
-		t.Error("Id não foi retornado ao realizar a Full Search")
+	if len(results) != 1 || results[0].ID != int(id) {
+		t.Fatal("Id não foi retornado ao realizar a Full Search")
 	}
-	if val.NoteText != note.NoteText {
+	if results[0].NoteText != note.NoteText {
 		t.Error("Texto da nota retornado pela busca está divergente do esperado")
 	}
 
-	fmt.Println(results[int(id)])
+	fmt.Println(results[0])
+}
+
+func TestDueReminders(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+
+	notes := []file.Note{
+		{CreatedAt: 1, NoteText: "vencido", Reminder: 100},
+		{CreatedAt: 1, NoteText: "futuro", Reminder: 300},
+		{CreatedAt: 1, NoteText: "sem lembrete", Reminder: 0},
+	}
+	for i := range notes {
+		if _, err := handler.InsertNote(&notes[i], ctx); err != nil {
+			t.Fatalf("Erro ao inserir nota: %v", err)
+		}
+	}
+
+	due, err := handler.DueReminders(ctx, 200)
+	if err != nil {
+		t.Fatalf("Erro ao consultar lembretes: %v", err)
+	}
+	if len(due) != 1 || due[0].NoteText != "vencido" {
+		t.Errorf("Esperado somente o lembrete vencido, obtido %v", due)
+	}
+}
+
+func TestSetReminder(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+
+	id, _ := handler.InsertNote(&file.Note{CreatedAt: 1, NoteText: "nota"}, ctx)
+	rowsAffected, err := handler.SetReminder(ctx, int(id), 500, 60)
+	if err != nil {
+		t.Fatalf("Erro ao definir lembrete: %v", err)
+	}
+	if rowsAffected != 1 {
+		t.Errorf("Esperado 1 linha afetada, obtido %d", rowsAffected)
+	}
+
+	note, err := handler.GetNote(ctx, int(id))
+	if err != nil {
+		t.Fatalf("Erro ao buscar nota: %v", err)
+	}
+	if note.Reminder != 500 || note.PlusReminder != 60 || note.NoteText != "nota" {
+		t.Errorf("Lembrete não gravado corretamente: %+v", note)
+	}
+}
+
+func TestEachNote(t *testing.T) {
+	handler := setupTestDB(t)
+	ctx := context.Background()
+
+	for _, text := range []string{"primeira #a", "segunda", "terceira #a"} {
+		if _, err := handler.InsertNote(&file.Note{CreatedAt: 1, NoteText: text}, ctx); err != nil {
+			t.Fatalf("Erro ao inserir nota: %v", err)
+		}
+	}
+
+	var texts []string
+	err := handler.EachNote(ctx, file.NoteFilter{Tag: "a"}, func(n file.Note) error {
+		texts = append(texts, n.NoteText)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Erro ao percorrer notas: %v", err)
+	}
+	if len(texts) != 2 || texts[0] != "primeira #a" || texts[1] != "terceira #a" {
+		t.Errorf("Notas percorridas fora do esperado: %v", texts)
+	}
+
+	stop := errors.New("parar")
+	calls := 0
+	err = handler.EachNote(ctx, file.NoteFilter{}, func(file.Note) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("Erro de fn deveria interromper a leitura: %v após %d chamadas", err, calls)
+	}
 }
@@ -0,0 +1,99 @@
+// Package scheduler dispara os lembretes vencidos das notas.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gustavo-silva98/adnotes/internal/repository/file"
+)
+
+// DefaultInterval é o intervalo padrão entre consultas de lembretes vencidos.
+const DefaultInterval = 30 * time.Second
+
+// Scheduler consulta periodicamente o repositório e chama Fire para cada nota
+// com lembrete vencido. Now é injetável para permitir testes com relógio falso.
+type Scheduler struct {
+	DB       file.Writer
+	Now      func() time.Time
+	Interval time.Duration
+	Fire     func(note file.Note)
+	LogPath  string
+}
+
+// New cria um Scheduler com relógio real e intervalo padrão.
+func New(db file.Writer, fire func(note file.Note)) *Scheduler {
+	return &Scheduler{
+		DB:       db,
+		Now:      time.Now,
+		Interval: DefaultInterval,
+		Fire:     fire,
+	}
+}
+
+// Run executa Tick imediatamente e depois a cada Interval, até done ser fechado.
+func (s *Scheduler) Run(ctx context.Context, done <-chan struct{}) {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Tick(ctx); err != nil {
+			file.WriteLog(err.Error(), s.LogPath)
+		}
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick dispara todos os lembretes vencidos e os reagenda. Lembretes com
+// PlusReminder voltam a disparar a cada PlusReminder segundos; os demais são
+// limpos. Retorna quantos lembretes foram disparados.
+func (s *Scheduler) Tick(ctx context.Context) (int, error) {
+	now := s.Now().Unix()
+	due, err := s.DB.DueReminders(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao consultar lembretes: %v", err)
+	}
+
+	fired := 0
+	for _, note := range due {
+		// Reagenda antes de disparar para que uma falha no Fire não gere disparos repetidos.
+		next := NextOccurrence(int64(note.Reminder), int64(note.PlusReminder), now)
+		if _, err := s.DB.SetReminder(ctx, note.ID, int(next), note.PlusReminder); err != nil {
+			return fired, fmt.Errorf("erro ao reagendar lembrete da nota %d: %v", note.ID, err)
+		}
+		s.Fire(note)
+		fired++
+	}
+	return fired, nil
+}
+
+// NextOccurrence calcula o próximo disparo após now de um lembrete que venceu
+// em reminder e se repete a cada plus segundos. Retorna 0 quando não há repetição.
+func NextOccurrence(reminder, plus, now int64) int64 {
+	if plus <= 0 {
+		return 0
+	}
+	if reminder > now {
+		return reminder
+	}
+	missed := (now-reminder)/plus + 1
+	return reminder + missed*plus
+}
+
+// DefaultSnooze é o adiamento usado por lembretes sem intervalo de repetição.
+const DefaultSnooze = 10 * time.Minute
+
+// Snooze retorna o novo instante (unix) de um lembrete adiado em now. O
+// intervalo de repetição da nota é usado como adiamento; sem ele, DefaultSnooze.
+func Snooze(note file.Note, now time.Time) int64 {
+	interval := time.Duration(note.PlusReminder) * time.Second
+	if interval <= 0 {
+		interval = DefaultSnooze
+	}
+	return now.Add(interval).Unix()
+}
@@ -0,0 +1,126 @@
+package scheduler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gustavo-silva98/adnotes/internal/repository/file"
+	"github.com/gustavo-silva98/adnotes/internal/scheduler"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func setupScheduler(t *testing.T) (*scheduler.Scheduler, *file.SqliteHandler, *fakeClock, *[]file.Note) {
+	t.Helper()
+	handler, err := file.InitDB(":memory:", context.Background())
+	if err != nil {
+		t.Fatalf("Erro ao inicializar banco de teste - %v", err)
+	}
+	clock := &fakeClock{now: time.Unix(1_700_000_000, 0)}
+	fired := &[]file.Note{}
+	s := scheduler.New(handler, func(n file.Note) { *fired = append(*fired, n) })
+	s.Now = clock.Now
+	return s, handler, clock, fired
+}
+
+func insertReminder(t *testing.T, handler *file.SqliteHandler, text string, reminder, plus int) int {
+	t.Helper()
+	id, err := handler.InsertNote(&file.Note{CreatedAt: 1, NoteText: text, Reminder: reminder, PlusReminder: plus}, context.Background())
+	if err != nil {
+		t.Fatalf("Erro ao inserir nota - %v", err)
+	}
+	return int(id)
+}
+
+func TestTickFiresOnlyDueReminders(t *testing.T) {
+	s, handler, clock, fired := setupScheduler(t)
+	ctx := context.Background()
+	now := int(clock.now.Unix())
+
+	dueID := insertReminder(t, handler, "vencido", now-60, 0)
+	insertReminder(t, handler, "futuro", now+60, 0)
+	insertReminder(t, handler, "sem lembrete", 0, 0)
+
+	count, err := s.Tick(ctx)
+	if err != nil {
+		t.Fatalf("Erro no Tick - %v", err)
+	}
+	if count != 1 || len(*fired) != 1 || (*fired)[0].ID != dueID {
+		t.Fatalf("Esperado disparo somente da nota %d, obtido %v", dueID, *fired)
+	}
+
+	note, _ := handler.GetNote(ctx, dueID)
+	if note.Reminder != 0 {
+		t.Errorf("Lembrete único deveria ser limpo após o disparo, obtido %d", note.Reminder)
+	}
+
+	// Um segundo Tick no mesmo instante não pode disparar novamente.
+	if count, _ := s.Tick(ctx); count != 0 {
+		t.Errorf("Lembrete disparado em duplicidade")
+	}
+}
+
+func TestTickReschedulesRepeatingReminder(t *testing.T) {
+	s, handler, clock, fired := setupScheduler(t)
+	ctx := context.Background()
+	now := int(clock.now.Unix())
+	hour := int(time.Hour / time.Second)
+
+	id := insertReminder(t, handler, "a cada hora", now-10, hour)
+
+	if _, err := s.Tick(ctx); err != nil {
+		t.Fatalf("Erro no Tick - %v", err)
+	}
+	note, _ := handler.GetNote(ctx, id)
+	if note.Reminder != now-10+hour {
+		t.Errorf("Próximo disparo esperado %d, obtido %d", now-10+hour, note.Reminder)
+	}
+
+	clock.now = clock.now.Add(time.Hour)
+	if _, err := s.Tick(ctx); err != nil {
+		t.Fatalf("Erro no Tick - %v", err)
+	}
+	if len(*fired) != 2 {
+		t.Errorf("Esperados 2 disparos após uma hora, obtidos %d", len(*fired))
+	}
+}
+
+func TestNextOccurrence(t *testing.T) {
+	tests := []struct {
+		name                string
+		reminder, plus, now int64
+		want                int64
+	}{
+		{"sem repetição", 100, 0, 150, 0},
+		{"ainda no futuro", 200, 60, 150, 200},
+		{"um intervalo perdido", 100, 60, 150, 160},
+		{"vários intervalos perdidos", 100, 60, 400, 460},
+		{"exatamente no disparo", 100, 60, 100, 160},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scheduler.NextOccurrence(tt.reminder, tt.plus, tt.now); got != tt.want {
+				t.Errorf("NextOccurrence(%d, %d, %d) = %d, esperado %d", tt.reminder, tt.plus, tt.now, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnoozeUsesRepeatInterval(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+
+	got := scheduler.Snooze(file.Note{PlusReminder: 300}, now)
+	if got != now.Unix()+300 {
+		t.Errorf("Adiamento esperado de 300s, obtido %d", got-now.Unix())
+	}
+
+	got = scheduler.Snooze(file.Note{}, now)
+	if got != now.Add(scheduler.DefaultSnooze).Unix() {
+		t.Errorf("Adiamento padrão esperado, obtido %d", got-now.Unix())
+	}
+}
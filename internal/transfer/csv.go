@@ -0,0 +1,142 @@
+package transfer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var csvHeader = []string{"id", "created", "updated", "title", "notebook", "tags", "reminder", "repeat_seconds", "text"}
+
+// legacyCSVHeaders são os cabeçalhos de exports antigos, com uma só data em
+// "hour" e, antes disso, sem a coluna title. Continuam aceitos na importação.
+var legacyCSVHeaders = [][]string{
+	{"id", "hour", "title", "notebook", "tags", "reminder", "repeat_seconds", "text"},
+	{"id", "hour", "notebook", "tags", "reminder", "repeat_seconds", "text"},
+}
+
+// CSVExporter grava uma nota por linha, com as tags separadas por espaço.
+type CSVExporter struct {
+	w      *csv.Writer
+	header bool
+}
+
+func NewCSVExporter(w io.Writer) *CSVExporter {
+	return &CSVExporter{w: csv.NewWriter(w)}
+}
+
+func (e *CSVExporter) Write(r Record) error {
+	if !e.header {
+		e.header = true
+		if err := e.w.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+	reminder := ""
+	if r.Reminder != nil {
+		reminder = r.Reminder.Format(time.RFC3339)
+	}
+	return e.w.Write([]string{
+		strconv.Itoa(r.ID),
+		r.Created.Format(time.RFC3339),
+		r.Updated.Format(time.RFC3339),
+		r.Title,
+		r.Notebook,
+		strings.Join(r.Tags, " "),
+		reminder,
+		strconv.Itoa(r.Repeat),
+		r.Text,
+	})
+}
+
+func (e *CSVExporter) Close() error {
+	if !e.header {
+		e.header = true
+		if err := e.w.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// ReadCSV lê registros no formato gravado por CSVExporter ou por versões
+// anteriores dele.
+func ReadCSV(r io.Reader, fn func(Record) error) error {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return fmt.Errorf("erro ao ler cabeçalho CSV: %v", err)
+	}
+	known := false
+	for _, h := range append([][]string{csvHeader}, legacyCSVHeaders...) {
+		known = known || slices.Equal(header, h)
+	}
+	if !known {
+		return fmt.Errorf("cabeçalho CSV inesperado: %v", header)
+	}
+
+	for line := 2; ; line++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		fields := map[string]string{}
+		for i, name := range header {
+			fields[name] = row[i]
+		}
+		rec, err := parseCSVRow(fields)
+		if err != nil {
+			return fmt.Errorf("linha %d do CSV: %v", line, err)
+		}
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+}
+
+// parseCSVRow converte uma linha, indexada pelo nome da coluna. Colunas
+// ausentes no cabeçalho ficam vazias.
+func parseCSVRow(row map[string]string) (Record, error) {
+	var rec Record
+	var err error
+	if row["id"] != "" {
+		if rec.ID, err = strconv.Atoi(row["id"]); err != nil {
+			return rec, err
+		}
+	}
+	dates := map[string]*time.Time{"created": &rec.Created, "updated": &rec.Updated, "hour": &rec.Hour}
+	for name, dst := range dates {
+		if row[name] != "" {
+			if *dst, err = time.Parse(time.RFC3339, row[name]); err != nil {
+				return rec, err
+			}
+		}
+	}
+	rec.Title = row["title"]
+	rec.Notebook = row["notebook"]
+	if row["tags"] != "" {
+		rec.Tags = strings.Fields(row["tags"])
+	}
+	if row["reminder"] != "" {
+		at, err := time.Parse(time.RFC3339, row["reminder"])
+		if err != nil {
+			return rec, err
+		}
+		rec.Reminder = &at
+	}
+	if row["repeat_seconds"] != "" {
+		if rec.Repeat, err = strconv.Atoi(row["repeat_seconds"]); err != nil {
+			return rec, err
+		}
+	}
+	rec.Text = row["text"]
+	return rec, nil
+}
@@ -0,0 +1,236 @@
+package transfer
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gustavo-silva98/adnotes/internal/repository/file"
+)
+
+// FileError é a falha ao importar um arquivo, ou um item dele, que não
+// interrompe o restante da importação.
+type FileError struct {
+	Path string
+	Err  error
+}
+
+func (e FileError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Path, e.Err)
+}
+
+// ImportResult resume uma importação.
+type ImportResult struct {
+	Imported   int
+	Duplicates int
+	Errors     []FileError
+}
+
+// importExtensions são os arquivos lidos ao importar um diretório.
+var importExtensions = map[string]bool{".md": true, ".markdown": true, ".txt": true}
+
+// Importer insere notas vindas de arquivos, ignorando as que já existem no
+// banco com o mesmo texto.
+type Importer struct {
+	DB file.Writer
+	// Now data as notas de JSON e CSV sem data; arquivos sem data usam a
+	// modificação.
+	Now func() time.Time
+
+	seen      map[[sha256.Size]byte]bool
+	notebooks []file.Notebook
+}
+
+func NewImporter(db file.Writer) *Importer {
+	return &Importer{DB: db, Now: time.Now}
+}
+
+// contentHash identifica notas iguais, desconsiderando espaços nas pontas e
+// finais de linha do Windows.
+func contentHash(text string) [sha256.Size]byte {
+	return sha256.Sum256([]byte(strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))))
+}
+
+// loadHashes lê o hash de todas as notas do banco antes da primeira inserção.
+func (im *Importer) loadHashes(ctx context.Context) error {
+	if im.seen != nil {
+		return nil
+	}
+	seen := map[[sha256.Size]byte]bool{}
+	err := im.DB.EachNote(ctx, file.NoteFilter{}, func(n file.Note) error {
+		seen[contentHash(n.NoteText)] = true
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao ler notas existentes: %v", err)
+	}
+	notebooks, err := im.DB.ListNotebooks(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao listar cadernos: %v", err)
+	}
+	im.seen = seen
+	im.notebooks = notebooks
+	return nil
+}
+
+// Import lê path, que pode ser um diretório com arquivos .md/.txt (lido
+// recursivamente), um arquivo .md/.txt, um export .json ou .csv. Só retorna
+// erro se não for possível começar; falhas em arquivos ficam em ImportResult.Errors.
+func (im *Importer) Import(ctx context.Context, path string) (ImportResult, error) {
+	var result ImportResult
+	info, err := os.Stat(path)
+	if err != nil {
+		return result, err
+	}
+	if err := im.loadHashes(ctx); err != nil {
+		return result, err
+	}
+
+	if !info.IsDir() {
+		im.importFile(ctx, path, &result)
+		return result, nil
+	}
+
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			result.Errors = append(result.Errors, FileError{Path: p, Err: err})
+			return nil
+		}
+		if d.IsDir() {
+			if p != path && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if importExtensions[strings.ToLower(filepath.Ext(p))] {
+			im.importFile(ctx, p, &result)
+		}
+		return ctx.Err()
+	})
+	return result, err
+}
+
+func (im *Importer) importFile(ctx context.Context, path string, result *ImportResult) {
+	fail := func(p string, err error) {
+		result.Errors = append(result.Errors, FileError{Path: p, Err: err})
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		fail(path, err)
+		return
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".csv":
+		read := ReadJSON
+		if strings.EqualFold(filepath.Ext(path), ".csv") {
+			read = ReadCSV
+		}
+		item := 0
+		err := read(f, func(rec Record) error {
+			item++
+			if err := im.insert(ctx, rec, result); err != nil {
+				fail(fmt.Sprintf("%v#%d", path, item), err)
+			}
+			return nil
+		})
+		if err != nil {
+			fail(path, err)
+		}
+	default:
+		rec, err := ReadMarkdown(f)
+		if err != nil {
+			fail(path, err)
+			return
+		}
+		if created, _ := rec.Dates(); created.IsZero() {
+			if info, err := f.Stat(); err == nil {
+				rec.Updated = info.ModTime()
+			}
+		}
+		if err := im.insert(ctx, rec, result); err != nil {
+			fail(path, err)
+		}
+	}
+}
+
+// insert grava o registro e suas tags, contando duplicadas sem gravá-las.
+func (im *Importer) insert(ctx context.Context, rec Record, result *ImportResult) error {
+	if strings.TrimSpace(rec.Text) == "" {
+		return fmt.Errorf("nota vazia")
+	}
+	hash := contentHash(rec.Text)
+	if im.seen[hash] {
+		result.Duplicates++
+		return nil
+	}
+
+	created, updated := rec.Dates()
+	if created.IsZero() {
+		now := im.Now()
+		created, updated = now, now
+	}
+	note := file.Note{
+		CreatedAt:    created.Unix(),
+		UpdatedAt:    updated.Unix(),
+		Title:        rec.Title,
+		NoteText:     rec.Text,
+		PlusReminder: rec.Repeat,
+		Tags:         rec.Tags,
+	}
+	if rec.Reminder != nil {
+		note.Reminder = int(rec.Reminder.Unix())
+	}
+	// Tags inválidas são recusadas antes de criar o caderno da nota.
+	for _, tag := range rec.Tags {
+		if _, err := file.NormalizeTag(tag); err != nil {
+			return err
+		}
+	}
+	if rec.Notebook != "" {
+		id, err := im.notebook(ctx, rec.Notebook)
+		if err != nil {
+			return err
+		}
+		note.NotebookID = id
+	}
+
+	// A nota e as tags entram juntas: uma falha não deixa nota pela metade.
+	if _, err := im.DB.InsertNote(&note, ctx); err != nil {
+		return err
+	}
+	im.seen[hash] = true
+	result.Imported++
+	return nil
+}
+
+// notebook retorna o caderno com o caminho informado, criando-o (e o pai,
+// se for um subcaderno) quando ainda não existir.
+func (im *Importer) notebook(ctx context.Context, path string) (int, error) {
+	if id, ok := FindNotebook(im.notebooks, path); ok {
+		return id, nil
+	}
+
+	parentID := 0
+	name := strings.Trim(path, "/ ")
+	if parent, child, nested := strings.Cut(name, "/"); nested {
+		id, err := im.notebook(ctx, parent)
+		if err != nil {
+			return 0, err
+		}
+		parentID, name = id, child
+	}
+	id, err := im.DB.CreateNotebook(ctx, name, parentID)
+	if err != nil {
+		return 0, err
+	}
+	im.notebooks = append(im.notebooks, file.Notebook{ID: int(id), Name: strings.TrimSpace(name), ParentID: parentID})
+	return int(id), nil
+}
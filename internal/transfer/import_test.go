@@ -0,0 +1,200 @@
+package transfer_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gustavo-silva98/adnotes/internal/repository/file"
+	"github.com/gustavo-silva98/adnotes/internal/transfer"
+)
+
+func newDB(t *testing.T) *file.SqliteHandler {
+	t.Helper()
+	db, err := file.InitDB(filepath.Join(t.TempDir(), "banco.db"), context.Background())
+	if err != nil {
+		t.Fatalf("Erro ao criar banco: %v", err)
+	}
+	t.Cleanup(func() { db.DB.Close() })
+	return db
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func allNotes(t *testing.T, db *file.SqliteHandler) []file.Note {
+	t.Helper()
+	var notes []file.Note
+	if err := db.EachNote(context.Background(), file.NoteFilter{}, func(n file.Note) error {
+		notes = append(notes, n)
+		return nil
+	}); err != nil {
+		t.Fatalf("Erro ao ler notas: %v", err)
+	}
+	return notes
+}
+
+func TestImportDirectory(t *testing.T) {
+	db := newDB(t)
+	ctx := context.Background()
+	if _, err := db.InsertNote(&file.Note{CreatedAt: 1, NoteText: "já existe"}, ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "reuniao.md"),
+		"---\ndate: 2020-05-01T08:00:00Z\ntags: [Projeto, cliente]\nreminder: 2030-01-01T09:00:00Z\nnotebook: Trabalho/Reuniões\n---\nPauta da reunião\n")
+	writeFile(t, filepath.Join(dir, "sub", "lista.txt"), "comprar pão\n")
+	writeFile(t, filepath.Join(dir, "sub", "z-copia.txt"), "  comprar pão  \n")
+	writeFile(t, filepath.Join(dir, "existente.md"), "já existe")
+	writeFile(t, filepath.Join(dir, "quebrado.md"), "---\ntags: [a]\nsem fechamento")
+	writeFile(t, filepath.Join(dir, "vazio.txt"), "\n")
+	writeFile(t, filepath.Join(dir, ".git", "ignorado.md"), "não importar")
+	writeFile(t, filepath.Join(dir, "imagem.png"), "binário")
+
+	modTime := time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(filepath.Join(dir, "sub", "lista.txt"), modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := transfer.NewImporter(db).Import(ctx, dir)
+	if err != nil {
+		t.Fatalf("Erro ao importar: %v", err)
+	}
+	if result.Imported != 2 || result.Duplicates != 2 || len(result.Errors) != 2 {
+		t.Fatalf("Resultado inesperado: %+v", result)
+	}
+
+	notes := allNotes(t, db)
+	byText := map[string]file.Note{}
+	for _, n := range notes {
+		byText[n.NoteText] = n
+	}
+
+	meeting := byText["Pauta da reunião"]
+	if meeting.UpdatedAt != time.Date(2020, 5, 1, 8, 0, 0, 0, time.UTC).Unix() {
+		t.Errorf("Data do front matter não preservada: %v", meeting.UpdatedAt)
+	}
+	if !reflect.DeepEqual(meeting.Tags, []string{"cliente", "projeto"}) {
+		t.Errorf("Tags do front matter não importadas: %v", meeting.Tags)
+	}
+	if meeting.Reminder != int(time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC).Unix()) {
+		t.Errorf("Lembrete não importado: %v", meeting.Reminder)
+	}
+	notebooks, _ := db.ListNotebooks(ctx)
+	if id, ok := transfer.FindNotebook(notebooks, "Trabalho/Reuniões"); !ok || meeting.NotebookID != id {
+		t.Errorf("Nota deveria estar no caderno criado, está em %d (%v)", meeting.NotebookID, notebooks)
+	}
+
+	if list := byText["comprar pão"]; list.UpdatedAt != modTime.Unix() {
+		t.Errorf("Arquivo sem data deveria usar a modificação: %v", list.UpdatedAt)
+	}
+}
+
+func TestImportJSONExportIntoNewDatabase(t *testing.T) {
+	source := seedDB(t)
+	var buf bytes.Buffer
+	if _, err := transfer.Export(context.Background(), source, file.NoteFilter{}, transfer.NewJSONExporter(&buf)); err != nil {
+		t.Fatalf("Erro ao exportar: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "notas.json")
+	writeFile(t, path, buf.String())
+
+	target := newDB(t)
+	im := transfer.NewImporter(target)
+	result, err := im.Import(context.Background(), path)
+	if err != nil || result.Imported != 3 || len(result.Errors) != 0 {
+		t.Fatalf("Importação do JSON: %+v (%v)", result, err)
+	}
+
+	want := expectedRecords(t, source)
+	got := expectedRecords(t, target)
+	for i := range want {
+		// Os ids são atribuídos pelo banco de destino.
+		want[i].ID, got[i].ID = 0, 0
+	}
+	assertSameRecords(t, got, want)
+
+	again, _ := im.Import(context.Background(), path)
+	if again.Imported != 0 || again.Duplicates != 3 {
+		t.Errorf("Reimportar deveria só contar duplicadas: %+v", again)
+	}
+}
+
+func TestImportPreservesCreatedAndUpdated(t *testing.T) {
+	source := seedDB(t)
+	dir := t.TempDir()
+	mdExp, err := transfer.NewMarkdownExporter(filepath.Join(dir, "md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var jsonBuf, csvBuf bytes.Buffer
+	exporters := map[string]transfer.Exporter{
+		filepath.Join(dir, "notas.json"): transfer.NewJSONExporter(&jsonBuf),
+		filepath.Join(dir, "notas.csv"):  transfer.NewCSVExporter(&csvBuf),
+		filepath.Join(dir, "md"):         mdExp,
+	}
+	for _, exp := range exporters {
+		if _, err := transfer.Export(context.Background(), source, file.NoteFilter{}, exp); err != nil {
+			t.Fatalf("Erro ao exportar: %v", err)
+		}
+	}
+	writeFile(t, filepath.Join(dir, "notas.json"), jsonBuf.String())
+	writeFile(t, filepath.Join(dir, "notas.csv"), csvBuf.String())
+
+	var edited file.Note
+	for _, n := range allNotes(t, source) {
+		if n.CreatedAt != n.UpdatedAt {
+			edited = n
+		}
+	}
+	if edited.ID == 0 {
+		t.Fatal("A semente deveria ter uma nota editada depois de criada")
+	}
+
+	for path := range exporters {
+		target := newDB(t)
+		result, err := transfer.NewImporter(target).Import(context.Background(), path)
+		if err != nil || result.Imported != 3 || len(result.Errors) != 0 {
+			t.Fatalf("Importação de %v: %+v (%v)", filepath.Base(path), result, err)
+		}
+		for _, n := range allNotes(t, target) {
+			if n.NoteText == edited.NoteText && (n.CreatedAt != edited.CreatedAt || n.UpdatedAt != edited.UpdatedAt) {
+				t.Errorf("%v: datas %d/%d, esperado %d/%d", filepath.Base(path), n.CreatedAt, n.UpdatedAt, edited.CreatedAt, edited.UpdatedAt)
+			}
+		}
+	}
+}
+
+func TestImportJSONReportsBadItems(t *testing.T) {
+	db := newDB(t)
+	path := filepath.Join(t.TempDir(), "notas.json")
+	writeFile(t, path, `[{"text": "boa"}, {"text": "tag ruim", "tags": ["com espaço"]}, {"text": ""}]`)
+
+	im := transfer.NewImporter(db)
+	im.Now = func() time.Time { return time.Unix(1234, 0) }
+	result, err := im.Import(context.Background(), path)
+	if err != nil {
+		t.Fatalf("Erro ao importar: %v", err)
+	}
+	if result.Imported != 1 || len(result.Errors) != 2 {
+		t.Fatalf("Resultado inesperado: %+v", result)
+	}
+	if result.Errors[0].Path != path+"#2" {
+		t.Errorf("Erro deveria apontar o item: %v", result.Errors[0].Path)
+	}
+	if notes := allNotes(t, db); notes[0].UpdatedAt != 1234 {
+		t.Errorf("Nota sem hour deveria usar Now: %v", notes[0].UpdatedAt)
+	}
+}
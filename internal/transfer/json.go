@@ -0,0 +1,62 @@
+package transfer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// JSONExporter grava um array JSON, um registro por linha, à medida que os
+// registros chegam.
+type JSONExporter struct {
+	w     io.Writer
+	count int
+}
+
+func NewJSONExporter(w io.Writer) *JSONExporter {
+	return &JSONExporter{w: w}
+}
+
+func (e *JSONExporter) Write(r Record) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	sep := ",\n  "
+	if e.count == 0 {
+		sep = "[\n  "
+	}
+	e.count++
+	_, err = fmt.Fprintf(e.w, "%s%s", sep, data)
+	return err
+}
+
+func (e *JSONExporter) Close() error {
+	end := "\n]\n"
+	if e.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(e.w, end)
+	return err
+}
+
+// ReadJSON lê um array JSON de registros, decodificando um de cada vez.
+func ReadJSON(r io.Reader, fn func(Record) error) error {
+	dec := json.NewDecoder(r)
+	if tok, err := dec.Token(); err != nil {
+		return fmt.Errorf("erro ao ler JSON: %v", err)
+	} else if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("JSON deve ser um array de notas")
+	}
+	for i := 0; dec.More(); i++ {
+		var rec Record
+		if err := dec.Decode(&rec); err != nil {
+			return fmt.Errorf("nota %d do JSON: %v", i+1, err)
+		}
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+	_, err := dec.Token()
+	return err
+}
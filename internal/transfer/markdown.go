@@ -0,0 +1,263 @@
+package transfer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// MarkdownExporter grava cada nota em um arquivo .md dentro de um diretório,
+// com os metadados em front matter YAML.
+type MarkdownExporter struct {
+	dir string
+}
+
+// NewMarkdownExporter cria o diretório de destino se necessário.
+func NewMarkdownExporter(dir string) (*MarkdownExporter, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("erro ao criar diretório %v: %v", dir, err)
+	}
+	return &MarkdownExporter{dir: dir}, nil
+}
+
+func (e *MarkdownExporter) Write(r Record) error {
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, r); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(e.dir, MarkdownFileName(r)), buf.Bytes(), 0644)
+}
+
+func (e *MarkdownExporter) Close() error { return nil }
+
+// MarkdownFileName gera o nome do arquivo a partir do id e da primeira linha
+// da nota, como "12-reuniao-de-planejamento.md".
+func MarkdownFileName(r Record) string {
+	firstLine, _, _ := strings.Cut(strings.TrimSpace(r.Text), "\n")
+
+	var slug strings.Builder
+	dash := false
+	for _, c := range strings.ToLower(firstLine) {
+		if unicode.IsLetter(c) || unicode.IsDigit(c) {
+			slug.WriteRune(c)
+			dash = false
+		} else if !dash && slug.Len() > 0 {
+			slug.WriteByte('-')
+			dash = true
+		}
+		if slug.Len() >= 40 {
+			break
+		}
+	}
+	name := strings.Trim(slug.String(), "-")
+	if name == "" {
+		name = "nota"
+	}
+	return fmt.Sprintf("%d-%s.md", r.ID, name)
+}
+
+// WriteMarkdown grava o registro com front matter seguido do texto da nota.
+func WriteMarkdown(w io.Writer, r Record) error {
+	var b strings.Builder
+	b.WriteString("---\n")
+	if r.ID != 0 {
+		fmt.Fprintf(&b, "id: %d\n", r.ID)
+	}
+	fmt.Fprintf(&b, "created: %v\n", r.Created.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "updated: %v\n", r.Updated.UTC().Format(time.RFC3339))
+	if r.Title != "" {
+		fmt.Fprintf(&b, "title: %v\n", strconv.Quote(r.Title))
+	}
+	if r.Notebook != "" {
+		fmt.Fprintf(&b, "notebook: %v\n", strconv.Quote(r.Notebook))
+	}
+	if len(r.Tags) > 0 {
+		fmt.Fprintf(&b, "tags: [%v]\n", strings.Join(r.Tags, ", "))
+	}
+	if r.Reminder != nil {
+		fmt.Fprintf(&b, "reminder: %v\n", r.Reminder.UTC().Format(time.RFC3339))
+	}
+	if r.Repeat != 0 {
+		fmt.Fprintf(&b, "repeat_seconds: %d\n", r.Repeat)
+	}
+	b.WriteString("---\n")
+	b.WriteString(r.Text)
+	b.WriteString("\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ReadMarkdown lê uma nota Markdown. O front matter é opcional; sem ele, o
+// arquivo inteiro vira o texto da nota e as datas ficam zeradas.
+func ReadMarkdown(r io.Reader) (Record, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Record{}, err
+	}
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+
+	var rec Record
+	if rest, ok := strings.CutPrefix(text, "---\n"); ok {
+		header, body, found := strings.Cut(rest, "\n---\n")
+		if !found {
+			if h, ok := strings.CutSuffix(rest, "\n---"); ok {
+				header, body, found = h, "", true
+			}
+		}
+		if !found {
+			return Record{}, fmt.Errorf("front matter sem o \"---\" de fechamento")
+		}
+		fields, err := parseFrontMatter(header)
+		if err != nil {
+			return Record{}, err
+		}
+		if err := rec.apply(fields); err != nil {
+			return Record{}, err
+		}
+		text = body
+	}
+	rec.Text = strings.TrimSuffix(text, "\n")
+	return rec, nil
+}
+
+// parseFrontMatter entende o subconjunto de YAML usado em notas: "chave: valor",
+// listas "[a, b]" e listas em bloco com "- item". Cada chave vira uma lista de valores.
+func parseFrontMatter(header string) (map[string][]string, error) {
+	fields := map[string][]string{}
+	var current string
+
+	scanner := bufio.NewScanner(strings.NewReader(header))
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if item, ok := strings.CutPrefix(trimmed, "- "); ok && current != "" {
+			fields[current] = append(fields[current], unquote(item))
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("front matter linha %d: esperado \"chave: valor\"", n)
+		}
+		current = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		switch {
+		case value == "":
+			fields[current] = nil
+		case strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]"):
+			var items []string
+			for _, item := range strings.Split(value[1:len(value)-1], ",") {
+				if item = unquote(strings.TrimSpace(item)); item != "" {
+					items = append(items, item)
+				}
+			}
+			fields[current] = items
+		default:
+			fields[current] = []string{unquote(value)}
+		}
+	}
+	return fields, scanner.Err()
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		if u, err := strconv.Unquote(s); err == nil {
+			return u
+		}
+	}
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+	}
+	return s
+}
+
+// timeLayouts são os formatos de data aceitos no front matter.
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+func parseTime(s string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("data inválida %q", s)
+}
+
+// apply preenche o registro com os campos conhecidos do front matter; "hour" e
+// "date" valem como Hour. Outros campos são ignorados.
+func (r *Record) apply(fields map[string][]string) error {
+	first := func(key string) string {
+		if len(fields[key]) == 0 {
+			return ""
+		}
+		return fields[key][0]
+	}
+
+	var err error
+	if v := first("id"); v != "" {
+		if r.ID, err = strconv.Atoi(v); err != nil {
+			return fmt.Errorf("id inválido %q", v)
+		}
+	}
+	dates := []struct {
+		keys []string
+		dst  *time.Time
+	}{
+		{[]string{"created"}, &r.Created},
+		{[]string{"updated"}, &r.Updated},
+		{[]string{"hour", "date"}, &r.Hour},
+	}
+	for _, d := range dates {
+		for _, key := range d.keys {
+			if v := first(key); v != "" {
+				t, err := parseTime(v)
+				if err != nil {
+					return err
+				}
+				*d.dst = t.UTC()
+				break
+			}
+		}
+	}
+	r.Title = first("title")
+	r.Notebook = first("notebook")
+	for _, tag := range fields["tags"] {
+		// "tags: a, b" sem colchetes também é aceito.
+		for _, t := range strings.Split(tag, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				r.Tags = append(r.Tags, t)
+			}
+		}
+	}
+	if v := first("reminder"); v != "" {
+		at, err := parseTime(v)
+		if err != nil {
+			return err
+		}
+		at = at.UTC()
+		r.Reminder = &at
+	}
+	if v := first("repeat_seconds"); v != "" {
+		if r.Repeat, err = strconv.Atoi(v); err != nil {
+			return fmt.Errorf("repeat_seconds inválido %q", v)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+// Package transfer converte notas de e para arquivos externos: um diretório
+// de Markdown com front matter, um array JSON ou CSV.
+package transfer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gustavo-silva98/adnotes/internal/repository/file"
+)
+
+// Record é a forma de uma nota fora do banco. Datas são gravadas em UTC;
+// Notebook é o caminho do caderno, como "Trabalho/Projeto X".
+type Record struct {
+	ID      int       `json:"id,omitempty"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+	// Hour é a única data dos exports antigos, a da última edição. Só é lida;
+	// Dates a usa quando Created e Updated faltam.
+	Hour     time.Time  `json:"hour,omitzero"`
+	Title    string     `json:"title,omitempty"`
+	Notebook string     `json:"notebook,omitempty"`
+	Tags     []string   `json:"tags,omitempty"`
+	Reminder *time.Time `json:"reminder,omitempty"`
+	Repeat   int        `json:"repeat_seconds,omitempty"`
+	Text     string     `json:"text"`
+}
+
+// Exporter grava registros em um formato de saída.
+type Exporter interface {
+	Write(r Record) error
+	Close() error
+}
+
+// NotebookPaths mapeia o id de cada caderno para o seu caminho.
+func NotebookPaths(notebooks []file.Notebook) map[int]string {
+	names := map[int]string{}
+	for _, nb := range notebooks {
+		names[nb.ID] = nb.Name
+	}
+	paths := map[int]string{}
+	for _, nb := range notebooks {
+		if nb.ParentID != 0 {
+			paths[nb.ID] = names[nb.ParentID] + "/" + nb.Name
+		} else {
+			paths[nb.ID] = nb.Name
+		}
+	}
+	return paths
+}
+
+// NewRecord converte uma nota do banco, usando paths para nomear o caderno.
+func NewRecord(note file.Note, paths map[int]string) Record {
+	r := Record{
+		ID:       note.ID,
+		Created:  time.Unix(note.CreatedAt, 0).UTC(),
+		Updated:  time.Unix(note.UpdatedAt, 0).UTC(),
+		Title:    note.Title,
+		Notebook: paths[note.NotebookID],
+		Tags:     note.Tags,
+		Repeat:   note.PlusReminder,
+		Text:     note.NoteText,
+	}
+	if note.Reminder > 0 {
+		at := time.Unix(int64(note.Reminder), 0).UTC()
+		r.Reminder = &at
+	}
+	return r
+}
+
+// Dates retorna a criação e a última edição do registro, completando uma com
+// a outra e, nos exports antigos, com Hour. Ambas ficam zeradas se o registro
+// não trouxer data nenhuma.
+func (r Record) Dates() (created, updated time.Time) {
+	created, updated = r.Created, r.Updated
+	if updated.IsZero() {
+		updated = r.Hour
+	}
+	if updated.IsZero() {
+		updated = created
+	}
+	if created.IsZero() {
+		created = updated
+	}
+	return created, updated
+}
+
+// Export percorre as notas do filtro e as grava em exp, sem carregá-las todas
+// em memória. Retorna quantas notas foram exportadas; exp é sempre fechado.
+func Export(ctx context.Context, db file.Writer, filter file.NoteFilter, exp Exporter) (int, error) {
+	notebooks, err := db.ListNotebooks(ctx)
+	if err != nil {
+		exp.Close()
+		return 0, fmt.Errorf("erro ao listar cadernos: %v", err)
+	}
+	paths := NotebookPaths(notebooks)
+
+	count := 0
+	err = db.EachNote(ctx, filter, func(note file.Note) error {
+		if err := exp.Write(NewRecord(note, paths)); err != nil {
+			return fmt.Errorf("erro ao exportar nota %d: %w", note.ID, err)
+		}
+		count++
+		return nil
+	})
+	if closeErr := exp.Close(); err == nil {
+		err = closeErr
+	}
+	return count, err
+}
+
+// FindNotebook retorna o id do caderno com o caminho informado, sem
+// diferenciar maiúsculas de minúsculas.
+func FindNotebook(notebooks []file.Notebook, path string) (int, bool) {
+	for id, p := range NotebookPaths(notebooks) {
+		if strings.EqualFold(p, strings.Trim(path, "/ ")) {
+			return id, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,191 @@
+package transfer_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gustavo-silva98/adnotes/internal/repository/file"
+	"github.com/gustavo-silva98/adnotes/internal/transfer"
+)
+
+// seedDB cria um banco com notas que exercitam os casos difíceis de cada
+// formato: vírgulas e aspas, várias linhas, tags, lembrete e subcaderno.
+func seedDB(t *testing.T) *file.SqliteHandler {
+	t.Helper()
+	ctx := context.Background()
+	db, err := file.InitDB(filepath.Join(t.TempDir(), "banco.db"), ctx)
+	if err != nil {
+		t.Fatalf("Erro ao criar banco: %v", err)
+	}
+	t.Cleanup(func() { db.DB.Close() })
+
+	parent, _ := db.CreateNotebook(ctx, "Trabalho", 0)
+	child, _ := db.CreateNotebook(ctx, "Projeto X", int(parent))
+
+	notes := []file.Note{
+		{CreatedAt: 1700000000, NoteText: "Simples"},
+		{CreatedAt: 1700000060, UpdatedAt: 1700086400, Title: "Ata \"semanal\", revisão", NoteText: "Reunião, com \"aspas\" #projeto\n- [ ] item\n\n---\nfim", NotebookID: int(child)},
+		{CreatedAt: 1700000120, NoteText: "Lembrete semanal #casa", Reminder: 1700600000, PlusReminder: 604800},
+	}
+	for i := range notes {
+		if _, err := db.InsertNote(&notes[i], ctx); err != nil {
+			t.Fatalf("Erro ao inserir nota: %v", err)
+		}
+	}
+	return db
+}
+
+func expectedRecords(t *testing.T, db *file.SqliteHandler) []transfer.Record {
+	t.Helper()
+	ctx := context.Background()
+	notebooks, _ := db.ListNotebooks(ctx)
+	paths := transfer.NotebookPaths(notebooks)
+
+	var records []transfer.Record
+	err := db.EachNote(ctx, file.NoteFilter{}, func(n file.Note) error {
+		records = append(records, transfer.NewRecord(n, paths))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Erro ao ler notas: %v", err)
+	}
+	return records
+}
+
+func assertSameRecords(t *testing.T, got, want []transfer.Record) {
+	t.Helper()
+	sort.Slice(got, func(i, j int) bool { return got[i].ID < got[j].ID })
+	if len(got) != len(want) {
+		t.Fatalf("Esperados %d registros, obtidos %d", len(want), len(got))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("Registro %d diverge:\nobtido   %+v\nesperado %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	db := seedDB(t)
+	var buf bytes.Buffer
+	count, err := transfer.Export(context.Background(), db, file.NoteFilter{}, transfer.NewJSONExporter(&buf))
+	if err != nil || count != 3 {
+		t.Fatalf("Erro ao exportar: %v (%d notas)", err, count)
+	}
+
+	var got []transfer.Record
+	if err := transfer.ReadJSON(&buf, func(r transfer.Record) error {
+		got = append(got, r)
+		return nil
+	}); err != nil {
+		t.Fatalf("Erro ao ler JSON exportado: %v", err)
+	}
+	assertSameRecords(t, got, expectedRecords(t, db))
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	db := seedDB(t)
+	var buf bytes.Buffer
+	if _, err := transfer.Export(context.Background(), db, file.NoteFilter{}, transfer.NewCSVExporter(&buf)); err != nil {
+		t.Fatalf("Erro ao exportar: %v", err)
+	}
+
+	var got []transfer.Record
+	if err := transfer.ReadCSV(&buf, func(r transfer.Record) error {
+		got = append(got, r)
+		return nil
+	}); err != nil {
+		t.Fatalf("Erro ao ler CSV exportado: %v", err)
+	}
+	assertSameRecords(t, got, expectedRecords(t, db))
+}
+
+func TestReadLegacyCSV(t *testing.T) {
+	legacy := "id,hour,notebook,tags,reminder,repeat_seconds,text\n" +
+		"7,2025-01-02T03:04:05Z,Trabalho,a b,,0,texto antigo\n"
+
+	var got []transfer.Record
+	if err := transfer.ReadCSV(strings.NewReader(legacy), func(r transfer.Record) error {
+		got = append(got, r)
+		return nil
+	}); err != nil {
+		t.Fatalf("Erro ao ler CSV sem title: %v", err)
+	}
+	if len(got) != 1 || got[0].Title != "" || got[0].Notebook != "Trabalho" || got[0].Text != "texto antigo" {
+		t.Fatalf("Registro inesperado: %+v", got)
+	}
+	want := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	if created, updated := got[0].Dates(); !created.Equal(want) || !updated.Equal(want) {
+		t.Errorf("\"hour\" deveria valer como criação e edição: %v, %v", created, updated)
+	}
+}
+
+func TestMarkdownRoundTrip(t *testing.T) {
+	db := seedDB(t)
+	dir := filepath.Join(t.TempDir(), "notas")
+	exp, err := transfer.NewMarkdownExporter(dir)
+	if err != nil {
+		t.Fatalf("Erro ao criar exportador: %v", err)
+	}
+	if _, err := transfer.Export(context.Background(), db, file.NoteFilter{}, exp); err != nil {
+		t.Fatalf("Erro ao exportar: %v", err)
+	}
+
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.md"))
+	var got []transfer.Record
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatalf("Erro ao abrir %v: %v", path, err)
+		}
+		rec, err := transfer.ReadMarkdown(f)
+		f.Close()
+		if err != nil {
+			t.Fatalf("Erro ao ler %v: %v", path, err)
+		}
+		got = append(got, rec)
+	}
+	assertSameRecords(t, got, expectedRecords(t, db))
+
+	if _, err := os.Stat(filepath.Join(dir, "2-reunião-com-aspas-projeto.md")); err != nil {
+		t.Errorf("Nome de arquivo inesperado: %v", paths)
+	}
+}
+
+func TestExportFilterAndEmpty(t *testing.T) {
+	db := seedDB(t)
+	var buf bytes.Buffer
+	count, err := transfer.Export(context.Background(), db, file.NoteFilter{Tag: "casa"}, transfer.NewJSONExporter(&buf))
+	if err != nil || count != 1 {
+		t.Fatalf("Exportação filtrada: %v (%d notas)", err, count)
+	}
+
+	buf.Reset()
+	count, err = transfer.Export(context.Background(), db, file.NoteFilter{Tag: "inexistente"}, transfer.NewJSONExporter(&buf))
+	if err != nil || count != 0 || buf.String() != "[]\n" {
+		t.Errorf("Exportação vazia deveria gerar \"[]\": %q (%v)", buf.String(), err)
+	}
+}
+
+func TestReadMarkdownFrontMatterVariants(t *testing.T) {
+	src := "---\ntitle: ignorado\ndate: 2021-03-04 10:30\ntags:\n  - Projeto\n  - casa\n---\ntexto\n"
+	rec, err := transfer.ReadMarkdown(bytes.NewBufferString(src))
+	if err != nil {
+		t.Fatalf("Erro ao ler Markdown: %v", err)
+	}
+	want := time.Date(2021, 3, 4, 10, 30, 0, 0, time.Local).UTC()
+	if !rec.Hour.Equal(want) || rec.Text != "texto" || !reflect.DeepEqual(rec.Tags, []string{"Projeto", "casa"}) {
+		t.Errorf("Front matter mal interpretado: %+v", rec)
+	}
+
+	if _, err := transfer.ReadMarkdown(bytes.NewBufferString("---\nid: 1\nsem fechamento")); err == nil {
+		t.Error("Esperado erro para front matter sem fechamento")
+	}
+}